@@ -1,7 +1,11 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/guverz/pr-reviewer-service/internal/app"
 )
@@ -12,7 +16,12 @@ func main() {
 		log.Fatalf("failed to initialize application: %v", err)
 	}
 
-	if err := application.Run(); err != nil {
+	// SIGINT/SIGTERM запускают graceful shutdown; повторный сигнал завершает процесс сразу
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	context.AfterFunc(ctx, stop)
+
+	if err := application.Run(ctx); err != nil {
 		log.Fatalf("application stopped with error: %v", err)
 	}
 }
@@ -0,0 +1,373 @@
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/guverz/pr-reviewer-service/internal/domain"
+)
+
+const maxImportLineSize = 1 << 20
+
+// exportKindError — последняя запись выгрузки, оборванной из-за ошибки
+const exportKindError = "error"
+
+// Записи выгрузки в формате NDJSON: одна запись на строку
+type ExportPullRequestDTO struct {
+	PullRequestID     string                  `json:"pull_request_id"`
+	PullRequestName   string                  `json:"pull_request_name"`
+	AuthorID          string                  `json:"author_id"`
+	Status            string                  `json:"status"`
+	AssignedReviewers []string                `json:"assigned_reviewers"`
+	NeedMoreReviewers bool                    `json:"need_more_reviewers"`
+	ReviewerShortage  string                  `json:"reviewer_shortage,omitempty"`
+	RequiredSkills    []string                `json:"required_skills,omitempty"`
+	MatchedSkills     map[string][]string     `json:"matched_skills,omitempty"`
+	CreatedAt         time.Time               `json:"created_at"`
+	MergedAt          *time.Time              `json:"merged_at,omitempty"`
+	MergedBy          string                  `json:"merged_by,omitempty"`
+	Reassignments     []ExportReassignmentDTO `json:"reassignments,omitempty"`
+}
+
+type ExportReassignmentDTO struct {
+	OldReviewerID string    `json:"old_user_id"`
+	NewReviewerID string    `json:"new_user_id,omitempty"`
+	ActorID       string    `json:"actor_id,omitempty"`
+	At            time.Time `json:"at"`
+}
+
+type ExportUnavailabilityDTO struct {
+	ID                  string     `json:"id"`
+	UserID              string     `json:"user_id"`
+	Reason              string     `json:"reason"`
+	StartsAt            time.Time  `json:"starts_at"`
+	EndsAt              time.Time  `json:"ends_at"`
+	ReassignReviews     bool       `json:"reassign_reviews"`
+	ReviewsReassignedAt *time.Time `json:"reviews_reassigned_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+}
+
+type ExportRotationDTO struct {
+	TeamName           string    `json:"team_name"`
+	Strategy           string    `json:"strategy"`
+	LastAssignedUserID string    `json:"last_assigned_user_id,omitempty"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+type ExportRecordDTO struct {
+	Type           string                   `json:"type"`
+	Team           *TeamDTO                 `json:"team,omitempty"`
+	User           *UserDTO                 `json:"user,omitempty"`
+	Role           *RoleAssignmentDTO       `json:"role,omitempty"`
+	Unavailability *ExportUnavailabilityDTO `json:"unavailability,omitempty"`
+	Rotation       *ExportRotationDTO       `json:"reviewer_rotation,omitempty"`
+	PullRequest    *ExportPullRequestDTO    `json:"pull_request,omitempty"`
+	Error          *ErrorDetail             `json:"error,omitempty"`
+}
+
+type ImportConflictDTO struct {
+	Line   int    `json:"line"`
+	Type   string `json:"type"`
+	ID     string `json:"id"`
+	Reason string `json:"reason"`
+}
+
+type ImportViolationDTO struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+type ImportReportDTO struct {
+	DryRun                 bool                 `json:"dry_run"`
+	Applied                bool                 `json:"applied"`
+	TeamsImported          int                  `json:"teams_imported"`
+	UsersImported          int                  `json:"users_imported"`
+	PullRequestsImported   int                  `json:"pull_requests_imported"`
+	RolesImported          int                  `json:"roles_imported"`
+	UnavailabilityImported int                  `json:"unavailability_imported"`
+	RotationsImported      int                  `json:"reviewer_rotations_imported"`
+	Conflicts              []ImportConflictDTO  `json:"conflicts"`
+	Errors                 []ImportViolationDTO `json:"errors"`
+}
+
+func toExportRecordDTO(rec domain.ImportRecord) ExportRecordDTO {
+	switch {
+	case rec.Team != nil:
+		team := ToTeamDTO(*rec.Team)
+		return ExportRecordDTO{Type: domain.ImportKindTeam, Team: &team}
+	case rec.User != nil:
+		user := ToUserDTO(*rec.User)
+		return ExportRecordDTO{Type: domain.ImportKindUser, User: &user}
+	case rec.Role != nil:
+		role := ToRoleAssignmentDTO(*rec.Role)
+		return ExportRecordDTO{Type: domain.ImportKindRole, Role: &role}
+	case rec.Unavailability != nil:
+		window := rec.Unavailability
+		return ExportRecordDTO{
+			Type: domain.ImportKindUnavailability,
+			Unavailability: &ExportUnavailabilityDTO{
+				ID:                  window.ID,
+				UserID:              window.UserID,
+				Reason:              string(window.Reason),
+				StartsAt:            window.StartsAt,
+				EndsAt:              window.EndsAt,
+				ReassignReviews:     window.ReassignReviews,
+				ReviewsReassignedAt: window.ReviewsReassignedAt,
+				CreatedAt:           window.CreatedAt,
+			},
+		}
+	case rec.Rotation != nil:
+		rotation := rec.Rotation
+		return ExportRecordDTO{
+			Type: domain.ImportKindRotation,
+			Rotation: &ExportRotationDTO{
+				TeamName:           rotation.TeamName,
+				Strategy:           string(rotation.Strategy),
+				LastAssignedUserID: rotation.LastUserID,
+				UpdatedAt:          rotation.UpdatedAt,
+			},
+		}
+	default:
+		pr := rec.PullRequest
+		return ExportRecordDTO{
+			Type: domain.ImportKindPullRequest,
+			PullRequest: &ExportPullRequestDTO{
+				PullRequestID:     pr.ID,
+				PullRequestName:   pr.Name,
+				AuthorID:          pr.AuthorID,
+				Status:            string(pr.Status),
+				AssignedReviewers: pr.AssignedReviewers,
+				NeedMoreReviewers: pr.NeedMoreReviewers,
+				ReviewerShortage:  string(pr.ReviewerShortage),
+				RequiredSkills:    pr.RequiredSkills,
+				MatchedSkills:     pr.MatchedSkills,
+				CreatedAt:         pr.CreatedAt,
+				MergedAt:          pr.MergedAt,
+				MergedBy:          pr.MergedBy,
+				Reassignments:     toExportReassignmentDTOs(pr.Reassignments),
+			},
+		}
+	}
+}
+
+func toExportReassignmentDTOs(reassignments []domain.ReviewerReassignment) []ExportReassignmentDTO {
+	if len(reassignments) == 0 {
+		return nil
+	}
+	dtos := make([]ExportReassignmentDTO, len(reassignments))
+	for i, r := range reassignments {
+		dtos[i] = ExportReassignmentDTO{
+			OldReviewerID: r.OldReviewerID,
+			NewReviewerID: r.NewReviewerID,
+			ActorID:       r.ActorID,
+			At:            r.At,
+		}
+	}
+	return dtos
+}
+
+// toImportRecord возвращает false, если тип записи неизвестен или её содержимое отсутствует
+func toImportRecord(dto ExportRecordDTO, line int) (domain.ImportRecord, bool) {
+	rec := domain.ImportRecord{Line: line}
+	switch {
+	case dto.Type == domain.ImportKindTeam && dto.Team != nil:
+		team := ToTeam(*dto.Team)
+		rec.Team = &team
+	case dto.Type == domain.ImportKindUser && dto.User != nil:
+		rec.User = &domain.User{
+			ID:             dto.User.UserID,
+			Username:       dto.User.Username,
+			TeamName:       dto.User.TeamName,
+			IsActive:       dto.User.IsActive,
+			MaxOpenReviews: dto.User.MaxOpenReviews,
+			Skills:         dto.User.Skills,
+		}
+	case dto.Type == domain.ImportKindRole && dto.Role != nil:
+		role := ToRoleAssignment(*dto.Role)
+		rec.Role = &role
+	case dto.Type == domain.ImportKindUnavailability && dto.Unavailability != nil:
+		window := dto.Unavailability
+		rec.Unavailability = &domain.Unavailability{
+			ID:                  window.ID,
+			UserID:              window.UserID,
+			Reason:              domain.UnavailabilityReason(window.Reason),
+			StartsAt:            window.StartsAt,
+			EndsAt:              window.EndsAt,
+			ReassignReviews:     window.ReassignReviews,
+			ReviewsReassignedAt: window.ReviewsReassignedAt,
+			CreatedAt:           window.CreatedAt,
+		}
+	case dto.Type == domain.ImportKindRotation && dto.Rotation != nil:
+		rec.Rotation = &domain.ReviewerRotation{
+			TeamName:   dto.Rotation.TeamName,
+			Strategy:   domain.ReviewerStrategy(dto.Rotation.Strategy),
+			LastUserID: dto.Rotation.LastAssignedUserID,
+			UpdatedAt:  dto.Rotation.UpdatedAt,
+		}
+	case dto.Type == domain.ImportKindPullRequest && dto.PullRequest != nil:
+		pr := dto.PullRequest
+		reviewers := pr.AssignedReviewers
+		if reviewers == nil {
+			reviewers = []string{}
+		}
+		rec.PullRequest = &domain.PullRequest{
+			ID:                pr.PullRequestID,
+			Name:              pr.PullRequestName,
+			AuthorID:          pr.AuthorID,
+			Status:            domain.PullRequestStatus(pr.Status),
+			AssignedReviewers: reviewers,
+			NeedMoreReviewers: pr.NeedMoreReviewers,
+			ReviewerShortage:  domain.ReviewerShortage(pr.ReviewerShortage),
+			RequiredSkills:    pr.RequiredSkills,
+			MatchedSkills:     pr.MatchedSkills,
+			CreatedAt:         pr.CreatedAt,
+			MergedAt:          pr.MergedAt,
+			MergedBy:          pr.MergedBy,
+		}
+		for _, r := range pr.Reassignments {
+			rec.PullRequest.Reassignments = append(rec.PullRequest.Reassignments, domain.ReviewerReassignment{
+				OldReviewerID: r.OldReviewerID,
+				NewReviewerID: r.NewReviewerID,
+				ActorID:       r.ActorID,
+				At:            r.At,
+			})
+		}
+	default:
+		return rec, false
+	}
+	return rec, true
+}
+
+func toImportReportDTO(report domain.ImportReport, parseErrors []ImportViolationDTO) ImportReportDTO {
+	conflicts := make([]ImportConflictDTO, len(report.Conflicts))
+	for i, c := range report.Conflicts {
+		conflicts[i] = ImportConflictDTO{Line: c.Line, Type: c.Kind, ID: c.ID, Reason: c.Reason}
+	}
+	violations := append([]ImportViolationDTO{}, parseErrors...)
+	for _, v := range report.Violations {
+		violations = append(violations, ImportViolationDTO{Line: v.Line, Message: v.Message})
+	}
+	return ImportReportDTO{
+		DryRun:                 report.DryRun,
+		Applied:                report.Applied,
+		TeamsImported:          report.TeamsImported,
+		UsersImported:          report.UsersImported,
+		PullRequestsImported:   report.PullRequestsImported,
+		RolesImported:          report.RolesImported,
+		UnavailabilityImported: report.UnavailabilityImported,
+		RotationsImported:      report.RotationsImported,
+		Conflicts:              conflicts,
+		Errors:                 violations,
+	}
+}
+
+// GET /admin/export
+func (h *Handlers) Export(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	encoder := json.NewEncoder(w)
+	flusher, _ := w.(http.Flusher)
+	// Заголовки отправляются с первой записью, чтобы отказ до начала выгрузки
+	// (например, нет прав) вернулся обычным ответом с ошибкой
+	started := false
+	start := func() {
+		if !started {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			w.WriteHeader(http.StatusOK)
+			started = true
+		}
+	}
+
+	err := h.adminService.Export(r.Context(), func(rec domain.ImportRecord) error {
+		start()
+		if err := encoder.Encode(toExportRecordDTO(rec)); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	})
+	if err != nil && !started {
+		writeError(w, r, err)
+		return
+	}
+	start()
+	if err != nil {
+		// Статус 200 уже отправлен: клиент узнаёт об обрыве по последней записи с type=error,
+		// а импорт такой файл отклонит как запись неизвестного типа
+		zerolog.Ctx(r.Context()).Error().Err(err).Msg("export failed")
+		_, detail := errorDetail(r, err)
+		encoder.Encode(ExportRecordDTO{Type: exportKindError, Error: &detail})
+	}
+}
+
+// POST /admin/import?dry_run=true
+func (h *Handlers) Import(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	dryRun := false
+	if v := r.URL.Query().Get("dry_run"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			writeError(w, r, invalidParam("dry_run", "must be a boolean"))
+			return
+		}
+		dryRun = parsed
+	}
+
+	var records []domain.ImportRecord
+	var parseErrors []ImportViolationDTO
+
+	scanner := bufio.NewScanner(r.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxImportLineSize)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var dto ExportRecordDTO
+		if err := json.Unmarshal(scanner.Bytes(), &dto); err != nil {
+			parseErrors = append(parseErrors, ImportViolationDTO{Line: line, Message: "invalid JSON: " + err.Error()})
+			continue
+		}
+		rec, ok := toImportRecord(dto, line)
+		if !ok {
+			parseErrors = append(parseErrors, ImportViolationDTO{Line: line, Message: "unknown record type or empty payload"})
+			continue
+		}
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		writeError(w, r, invalidParam("body", "%v", err))
+		return
+	}
+
+	// При ошибках разбора импорт не применяем, но проверяем остальные записи
+	report, err := h.adminService.Import(r.Context(), records, dryRun || len(parseErrors) > 0)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	report.DryRun = dryRun
+
+	response := toImportReportDTO(*report, parseErrors)
+	statusCode := http.StatusOK
+	if len(response.Errors) > 0 {
+		statusCode = http.StatusUnprocessableEntity
+	}
+	WriteJSON(w, statusCode, response)
+}
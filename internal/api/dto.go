@@ -22,21 +22,86 @@ type TeamResponse struct {
 	Team TeamDTO `json:"team"`
 }
 
+type TeamMemberRenameDTO struct {
+	UserID      string `json:"user_id"`
+	OldUsername string `json:"old_username"`
+	NewUsername string `json:"new_username"`
+}
+
+type TeamDiffDTO struct {
+	Created     bool                  `json:"created"`
+	Added       []TeamMemberDTO       `json:"added"`
+	Removed     []TeamMemberDTO       `json:"removed"`
+	Renamed     []TeamMemberRenameDTO `json:"renamed"`
+	Activated   []string              `json:"activated"`
+	Deactivated []string              `json:"deactivated"`
+}
+
+type TeamSyncResponse struct {
+	Team TeamDTO     `json:"team"`
+	Diff TeamDiffDTO `json:"diff"`
+}
+
+type TeamListResponse struct {
+	Teams []TeamDTO `json:"teams"`
+}
+
+type RenameTeamRequest struct {
+	TeamName    string `json:"team_name"`
+	NewTeamName string `json:"new_team_name"`
+}
+
+// UpdateTeamRequest — тело PATCH /api/v1/teams/{team_name}: новое имя команды
+type UpdateTeamRequest struct {
+	TeamName string `json:"team_name"`
+}
+
+type DeleteTeamRequest struct {
+	TeamName       string `json:"team_name"`
+	ReassignToTeam string `json:"reassign_to_team,omitempty"`
+}
+
+type DeleteTeamResponse struct {
+	TeamName               string   `json:"team_name"`
+	ReassignedPullRequests []string `json:"reassigned_pull_requests"`
+}
+
 // User DTO
 type UserDTO struct {
 	UserID   string `json:"user_id"`
 	Username string `json:"username"`
 	TeamName string `json:"team_name"`
 	IsActive bool   `json:"is_active"`
+	// MaxOpenReviews: 0 — без ограничения
+	MaxOpenReviews int      `json:"max_open_reviews,omitempty"`
+	Skills         []string `json:"skills,omitempty"`
 }
 
 type UserResponse struct {
-	User UserDTO `json:"user"`
+	User      UserDTO `json:"user"`
+	UpdatedBy string  `json:"updated_by,omitempty"`
 }
 
 type SetActiveRequest struct {
 	UserID   string `json:"user_id"`
-	IsActive bool   `json:"is_active"`
+	IsActive *bool  `json:"is_active"`
+}
+
+type SetMaxOpenReviewsRequest struct {
+	UserID         string `json:"user_id"`
+	MaxOpenReviews *int   `json:"max_open_reviews"`
+}
+
+type SetSkillsRequest struct {
+	UserID string    `json:"user_id"`
+	Skills *[]string `json:"skills"`
+}
+
+// UpdateUserRequest — тело PATCH /api/v1/users/{user_id}; меняются только переданные поля
+type UpdateUserRequest struct {
+	IsActive       *bool     `json:"is_active"`
+	MaxOpenReviews *int      `json:"max_open_reviews"`
+	Skills         *[]string `json:"skills"`
 }
 
 // PullRequest DTO
@@ -46,8 +111,32 @@ type PullRequestDTO struct {
 	AuthorID          string   `json:"author_id"`
 	Status            string   `json:"status"`
 	AssignedReviewers []string `json:"assigned_reviewers"`
-	CreatedAt         *string  `json:"createdAt,omitempty"`
-	MergedAt          *string  `json:"mergedAt,omitempty"`
+	NeedMoreReviewers bool     `json:"need_more_reviewers"`
+	ReviewerShortage  string   `json:"reviewer_shortage,omitempty"`
+	RequiredSkills    []string `json:"required_skills,omitempty"`
+	// MatchedSkills: ID ревьюера → теги из required_skills, по которым он выбран
+	MatchedSkills  map[string][]string `json:"matched_skills,omitempty"`
+	AuthorTeamName string              `json:"author_team_name,omitempty"`
+	Reviewers      []ReviewerDTO       `json:"reviewers,omitempty"`
+	CreatedAt      *string             `json:"createdAt,omitempty"`
+	MergedAt       *string             `json:"mergedAt,omitempty"`
+	MergedBy       string              `json:"merged_by,omitempty"`
+	Reassignments  []ReassignmentDTO   `json:"reassignments,omitempty"`
+	Version        int64               `json:"version,omitempty"`
+}
+
+type ReassignmentDTO struct {
+	OldReviewerID string `json:"old_user_id"`
+	// Пусто, если ревьюер был снят без замены
+	NewReviewerID string `json:"new_user_id,omitempty"`
+	TriggeredBy   string `json:"triggered_by,omitempty"`
+	At            string `json:"at"`
+}
+
+type ReviewerDTO struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	IsActive bool   `json:"is_active"`
 }
 
 type PullRequestShortDTO struct {
@@ -62,9 +151,10 @@ type PullRequestResponse struct {
 }
 
 type CreatePRRequest struct {
-	PullRequestID   string `json:"pull_request_id"`
-	PullRequestName string `json:"pull_request_name"`
-	AuthorID        string `json:"author_id"`
+	PullRequestID   string   `json:"pull_request_id"`
+	PullRequestName string   `json:"pull_request_name"`
+	AuthorID        string   `json:"author_id"`
+	RequiredSkills  []string `json:"required_skills,omitempty"`
 }
 
 type MergePRRequest struct {
@@ -81,14 +171,81 @@ type ReassignResponse struct {
 	ReplacedBy string         `json:"replaced_by"`
 }
 
+type ListPullRequestsResponse struct {
+	PullRequests []PullRequestDTO `json:"pull_requests"`
+	NextCursor   *string          `json:"next_cursor,omitempty"`
+}
+
 type GetReviewResponse struct {
 	UserID       string                `json:"user_id"`
 	PullRequests []PullRequestShortDTO `json:"pull_requests"`
 }
 
+// Role DTO
+type RoleAssignmentDTO struct {
+	UserID   string `json:"user_id"`
+	Role     string `json:"role"`
+	TeamName string `json:"team_name,omitempty"`
+}
+
+type RoleListResponse struct {
+	Roles []RoleAssignmentDTO `json:"roles"`
+}
+
+// Unavailability DTO
+type UnavailabilityDTO struct {
+	ID                  string  `json:"id"`
+	UserID              string  `json:"user_id"`
+	Reason              string  `json:"reason"`
+	StartsAt            string  `json:"starts_at"`
+	EndsAt              string  `json:"ends_at"`
+	ReassignReviews     bool    `json:"reassign_reviews"`
+	ReviewsReassignedAt *string `json:"reviews_reassigned_at,omitempty"`
+}
+
+type CreateUnavailabilityRequest struct {
+	Reason          string `json:"reason"`
+	StartsAt        string `json:"starts_at"`
+	EndsAt          string `json:"ends_at"`
+	ReassignReviews bool   `json:"reassign_reviews"`
+}
+
+type UnavailabilityResponse struct {
+	Unavailability UnavailabilityDTO `json:"unavailability"`
+}
+
+type UnavailabilityListResponse struct {
+	UserID         string              `json:"user_id"`
+	Unavailability []UnavailabilityDTO `json:"unavailability"`
+}
+
+// ReviewerRotation DTO
+type ReviewerRotationDTO struct {
+	TeamName string `json:"team_name"`
+	Strategy string `json:"strategy"`
+	// LastAssignedUserID — последний участник, назначенный по очереди round-robin
+	LastAssignedUserID string  `json:"last_assigned_user_id,omitempty"`
+	UpdatedAt          *string `json:"updated_at,omitempty"`
+}
+
+type ReviewerRotationResponse struct {
+	Rotation ReviewerRotationDTO `json:"rotation"`
+}
+
+type SetReviewerStrategyRequest struct {
+	Strategy string `json:"strategy"`
+}
+
 // Error DTO
 type ErrorDetail struct {
-	Code    string `json:"code"`
+	Code       string              `json:"code"`
+	Message    string              `json:"message"`
+	Details    map[string]any      `json:"details,omitempty"`
+	Violations []FieldViolationDTO `json:"violations,omitempty"`
+}
+
+type FieldViolationDTO struct {
+	Field   string `json:"field"`
 	Message string `json:"message"`
 }
 
@@ -96,6 +253,19 @@ type ErrorResponse struct {
 	Error ErrorDetail `json:"error"`
 }
 
+// ProblemDetails — ошибка в формате RFC 7807 (application/problem+json).
+// Code, Details и Violations — расширения с теми же данными, что и в ErrorDetail.
+type ProblemDetails struct {
+	Type       string              `json:"type"`
+	Title      string              `json:"title"`
+	Status     int                 `json:"status"`
+	Detail     string              `json:"detail,omitempty"`
+	Instance   string              `json:"instance,omitempty"`
+	Code       string              `json:"code"`
+	Details    map[string]any      `json:"details,omitempty"`
+	Violations []FieldViolationDTO `json:"violations,omitempty"`
+}
+
 // Конвертеры из domain в DTO
 func ToTeamMemberDTO(m domain.TeamMember) TeamMemberDTO {
 	return TeamMemberDTO{
@@ -116,12 +286,41 @@ func ToTeamDTO(t domain.Team) TeamDTO {
 	}
 }
 
+func ToTeamDiffDTO(d domain.TeamDiff) TeamDiffDTO {
+	added := make([]TeamMemberDTO, len(d.Added))
+	for i, m := range d.Added {
+		added[i] = ToTeamMemberDTO(m)
+	}
+	removed := make([]TeamMemberDTO, len(d.Removed))
+	for i, m := range d.Removed {
+		removed[i] = ToTeamMemberDTO(m)
+	}
+	renamed := make([]TeamMemberRenameDTO, len(d.Renamed))
+	for i, r := range d.Renamed {
+		renamed[i] = TeamMemberRenameDTO{
+			UserID:      r.UserID,
+			OldUsername: r.OldUsername,
+			NewUsername: r.NewUsername,
+		}
+	}
+	return TeamDiffDTO{
+		Created:     d.Created,
+		Added:       added,
+		Removed:     removed,
+		Renamed:     renamed,
+		Activated:   d.Activated,
+		Deactivated: d.Deactivated,
+	}
+}
+
 func ToUserDTO(u domain.User) UserDTO {
 	return UserDTO{
-		UserID:   u.ID,
-		Username: u.Username,
-		TeamName: u.TeamName,
-		IsActive: u.IsActive,
+		UserID:         u.ID,
+		Username:       u.Username,
+		TeamName:       u.TeamName,
+		IsActive:       u.IsActive,
+		MaxOpenReviews: u.MaxOpenReviews,
+		Skills:         u.Skills,
 	}
 }
 
@@ -143,9 +342,48 @@ func ToPullRequestDTO(pr domain.PullRequest) PullRequestDTO {
 		AuthorID:          pr.AuthorID,
 		Status:            string(pr.Status),
 		AssignedReviewers: pr.AssignedReviewers,
+		NeedMoreReviewers: pr.NeedMoreReviewers,
+		ReviewerShortage:  string(pr.ReviewerShortage),
+		RequiredSkills:    pr.RequiredSkills,
+		MatchedSkills:     pr.MatchedSkills,
 		CreatedAt:         &createdAt,
 		MergedAt:          mergedAt,
+		MergedBy:          pr.MergedBy,
+		Reassignments:     toReassignmentDTOs(pr.Reassignments),
+		Version:           pr.Version,
+	}
+}
+
+func toReassignmentDTOs(reassignments []domain.ReviewerReassignment) []ReassignmentDTO {
+	if len(reassignments) == 0 {
+		return nil
+	}
+	dtos := make([]ReassignmentDTO, len(reassignments))
+	for i, r := range reassignments {
+		dtos[i] = ReassignmentDTO{
+			OldReviewerID: r.OldReviewerID,
+			NewReviewerID: r.NewReviewerID,
+			TriggeredBy:   r.ActorID,
+			At:            formatTime(r.At),
+		}
 	}
+	return dtos
+}
+
+func ToPullRequestDetailsDTO(d domain.PullRequestDetails) PullRequestDTO {
+	dto := ToPullRequestDTO(d.PullRequest)
+	if d.Author != nil {
+		dto.AuthorTeamName = d.Author.TeamName
+	}
+	dto.Reviewers = make([]ReviewerDTO, len(d.Reviewers))
+	for i, reviewer := range d.Reviewers {
+		dto.Reviewers[i] = ReviewerDTO{
+			UserID:   reviewer.ID,
+			Username: reviewer.Username,
+			IsActive: reviewer.IsActive,
+		}
+	}
+	return dto
 }
 
 func ToPullRequestShortDTO(pr domain.PullRequest) PullRequestShortDTO {
@@ -157,6 +395,47 @@ func ToPullRequestShortDTO(pr domain.PullRequest) PullRequestShortDTO {
 	}
 }
 
+func ToRoleAssignmentDTO(a domain.RoleAssignment) RoleAssignmentDTO {
+	return RoleAssignmentDTO{
+		UserID:   a.UserID,
+		Role:     string(a.Role),
+		TeamName: a.TeamName,
+	}
+}
+
+func ToUnavailabilityDTO(u domain.Unavailability) UnavailabilityDTO {
+	var reassignedAt *string
+	if u.ReviewsReassignedAt != nil {
+		formatted := formatTime(*u.ReviewsReassignedAt)
+		reassignedAt = &formatted
+	}
+
+	return UnavailabilityDTO{
+		ID:                  u.ID,
+		UserID:              u.UserID,
+		Reason:              string(u.Reason),
+		StartsAt:            formatTime(u.StartsAt),
+		EndsAt:              formatTime(u.EndsAt),
+		ReassignReviews:     u.ReassignReviews,
+		ReviewsReassignedAt: reassignedAt,
+	}
+}
+
+func ToReviewerRotationDTO(r domain.ReviewerRotation) ReviewerRotationDTO {
+	var updatedAt *string
+	if !r.UpdatedAt.IsZero() {
+		formatted := formatTime(r.UpdatedAt)
+		updatedAt = &formatted
+	}
+
+	return ReviewerRotationDTO{
+		TeamName:           r.TeamName,
+		Strategy:           string(r.Strategy),
+		LastAssignedUserID: r.LastUserID,
+		UpdatedAt:          updatedAt,
+	}
+}
+
 // Конвертеры из DTO в domain
 func ToTeam(dto TeamDTO) domain.Team {
 	members := make([]domain.TeamMember, len(dto.Members))
@@ -173,5 +452,23 @@ func ToTeam(dto TeamDTO) domain.Team {
 	}
 }
 
+func ToRoleAssignment(dto RoleAssignmentDTO) domain.RoleAssignment {
+	return domain.RoleAssignment{
+		UserID:   dto.UserID,
+		Role:     domain.Role(dto.Role),
+		TeamName: dto.TeamName,
+	}
+}
 
-
+// ToUnavailability ожидает запрос, прошедший Validate: время в нём уже разобрано без ошибок
+func ToUnavailability(userID string, dto CreateUnavailabilityRequest) domain.Unavailability {
+	startsAt, _ := time.Parse(time.RFC3339, dto.StartsAt)
+	endsAt, _ := time.Parse(time.RFC3339, dto.EndsAt)
+	return domain.Unavailability{
+		UserID:          userID,
+		Reason:          domain.UnavailabilityReason(dto.Reason),
+		StartsAt:        startsAt,
+		EndsAt:          endsAt,
+		ReassignReviews: dto.ReassignReviews,
+	}
+}
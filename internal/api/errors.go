@@ -2,47 +2,103 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
+	"mime"
 	"net/http"
+	"strings"
+
+	"github.com/rs/zerolog"
 
 	"github.com/guverz/pr-reviewer-service/internal/domain"
 )
 
-func WriteError(w http.ResponseWriter, err error) {
-	domainErr, ok := err.(domain.DomainError)
-	if !ok {
-		// Неизвестная ошибка
-		writeErrorResponse(w, http.StatusInternalServerError, domain.ErrorCodeNotFound, err.Error())
-		return
+const problemContentType = "application/problem+json"
+
+// errorStatuses сопоставляет класс ошибки со статусом ответа
+var errorStatuses = map[domain.ErrorKind]int{
+	domain.KindInvalidInput: http.StatusBadRequest,
+	domain.KindNotFound:     http.StatusNotFound,
+	domain.KindConflict:     http.StatusConflict,
+	domain.KindUnauthorized: http.StatusUnauthorized,
+	domain.KindForbidden:    http.StatusForbidden,
+	domain.KindInternal:     http.StatusInternalServerError,
+
+	domain.KindPreconditionFailed: http.StatusPreconditionFailed,
+	domain.KindUnprocessable:      http.StatusUnprocessableEntity,
+}
+
+// writeError пишет ошибку в формате, который запросил клиент: RFC 7807 при
+// Accept: application/problem+json, иначе — в конверте {"error": {...}}.
+// Внутренние ошибки логируются, а клиент получает только код INTERNAL.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	status, detail := errorDetail(r, err)
+	if status == http.StatusInternalServerError {
+		zerolog.Ctx(r.Context()).Error().Err(err).Msg("request failed")
 	}
 
-	// Маппинг кодов ошибок на HTTP статусы
-	statusCode := http.StatusInternalServerError
-	switch domainErr.Code {
-	case domain.ErrorCodeTeamExists, domain.ErrorCodePRExists:
-		statusCode = http.StatusBadRequest
-	case domain.ErrorCodeNotFound:
-		statusCode = http.StatusNotFound
-	case domain.ErrorCodePRMerged, domain.ErrorCodeNotAssigned, domain.ErrorCodeNoCandidate:
-		statusCode = http.StatusConflict
-	default:
-		statusCode = http.StatusInternalServerError
+	if acceptsProblem(r) {
+		w.Header().Set("Content-Type", problemContentType)
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(ProblemDetails{
+			Type:       "about:blank",
+			Title:      http.StatusText(status),
+			Status:     status,
+			Detail:     detail.Message,
+			Instance:   r.URL.Path,
+			Code:       detail.Code,
+			Details:    detail.Details,
+			Violations: detail.Violations,
+		})
+		return
 	}
 
-	writeErrorResponse(w, statusCode, domainErr.Code, domainErr.Message)
+	WriteJSON(w, status, ErrorResponse{Error: detail})
 }
 
-func writeErrorResponse(w http.ResponseWriter, statusCode int, code domain.ErrorCode, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
+func errorDetail(r *http.Request, err error) (int, ErrorDetail) {
+	var validationErr domain.ValidationError
+	if errors.As(err, &validationErr) {
+		violations := make([]FieldViolationDTO, len(validationErr.Violations))
+		for i, v := range validationErr.Violations {
+			violations[i] = FieldViolationDTO{Field: v.Field, Message: v.Message}
+		}
+		return http.StatusBadRequest, ErrorDetail{
+			Code:       string(domain.ErrorCodeValidation),
+			Message:    "request validation failed",
+			Violations: violations,
+		}
+	}
 
-	response := ErrorResponse{
-		Error: ErrorDetail{
-			Code:    string(code),
-			Message: message,
-		},
+	var domainErr domain.DomainError
+	if !errors.As(err, &domainErr) || domainErr.Kind() == domain.KindInternal {
+		return http.StatusInternalServerError, ErrorDetail{
+			Code:    string(domain.ErrorCodeInternal),
+			Message: "internal server error",
+		}
 	}
 
-	json.NewEncoder(w).Encode(response)
+	status := errorStatuses[domainErr.Kind()]
+	// Старые маршруты по исходной спецификации отвечают 400 на повторное создание команды или PR
+	if !isV1(r) && (domainErr.Code == domain.ErrorCodeTeamExists || domainErr.Code == domain.ErrorCodePRExists) {
+		status = http.StatusBadRequest
+	}
+
+	return status, ErrorDetail{
+		Code:    string(domainErr.Code),
+		Message: domainErr.Error(),
+		Details: domainErr.Details,
+	}
+}
+
+// acceptsProblem проверяет, что клиент явно просит application/problem+json
+func acceptsProblem(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err == nil && mediaType == problemContentType {
+			return true
+		}
+	}
+	return false
 }
 
 func WriteJSON(w http.ResponseWriter, statusCode int, data interface{}) {
@@ -50,6 +106,3 @@ func WriteJSON(w http.ResponseWriter, statusCode int, data interface{}) {
 	w.WriteHeader(statusCode)
 	json.NewEncoder(w).Encode(data)
 }
-
-
-
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ETag PR — его версия в кавычках, например "3". Клиент передаёт его в If-Match,
+// чтобы изменение не затёрло чужое: при несовпадении версии сервис отвечает 412.
+
+func setETag(w http.ResponseWriter, version int64) {
+	w.Header().Set("ETag", `"`+strconv.FormatInt(version, 10)+`"`)
+}
+
+// ifMatchVersion возвращает версию из заголовка If-Match; 0 — проверка не нужна (заголовка нет или "*")
+func ifMatchVersion(r *http.Request) (int64, error) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	if value == "" || value == "*" {
+		return 0, nil
+	}
+
+	// If-Match сравнивает ETag строго, поэтому слабые ETag (W/"...") не принимаются
+	unquoted, ok := strings.CutPrefix(value, `"`)
+	if ok {
+		unquoted, ok = strings.CutSuffix(unquoted, `"`)
+	}
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if !ok || err != nil || version <= 0 {
+		return 0, invalidParam("If-Match", `must be a single strong ETag of the form "<version>"`)
+	}
+	return version, nil
+}
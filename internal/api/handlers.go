@@ -1,28 +1,36 @@
 package api
 
 import (
-	"encoding/json"
 	"net/http"
 
-	"github.com/guverz/pr-reviewer-service/internal/domain"
+	"github.com/guverz/pr-reviewer-service/internal/auth"
 	"github.com/guverz/pr-reviewer-service/internal/service"
 )
 
 type Handlers struct {
-	teamService        *service.TeamService
-	userService        *service.UserService
-	pullRequestService *service.PullRequestService
+	teamService         *service.TeamService
+	userService         *service.UserService
+	pullRequestService  *service.PullRequestService
+	adminService        *service.AdminService
+	roleService         *service.RoleService
+	availabilityService *service.AvailabilityService
 }
 
 func NewHandlers(
 	teamService *service.TeamService,
 	userService *service.UserService,
 	pullRequestService *service.PullRequestService,
+	adminService *service.AdminService,
+	roleService *service.RoleService,
+	availabilityService *service.AvailabilityService,
 ) *Handlers {
 	return &Handlers{
-		teamService:        teamService,
-		userService:        userService,
-		pullRequestService: pullRequestService,
+		teamService:         teamService,
+		userService:         userService,
+		pullRequestService:  pullRequestService,
+		adminService:        adminService,
+		roleService:         roleService,
+		availabilityService: availabilityService,
 	}
 }
 
@@ -34,15 +42,15 @@ func (h *Handlers) AddTeam(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req TeamDTO
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteError(w, domain.NewDomainError(domain.ErrorCodeNotFound, "invalid request body"))
+	if err := decodeAndValidate(r, &req); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
 	team := ToTeam(req)
 	createdTeam, err := h.teamService.CreateTeam(r.Context(), team)
 	if err != nil {
-		WriteError(w, err)
+		writeError(w, r, err)
 		return
 	}
 
@@ -60,20 +68,137 @@ func (h *Handlers) GetTeam(w http.ResponseWriter, r *http.Request) {
 	}
 
 	teamName := r.URL.Query().Get("team_name")
-	if teamName == "" {
-		WriteError(w, domain.NewDomainError(domain.ErrorCodeNotFound, "team_name parameter is required"))
+	if err := validateTeamName("team_name", teamName); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
+	h.getTeam(w, r, teamName)
+}
+
+func (h *Handlers) getTeam(w http.ResponseWriter, r *http.Request, teamName string) {
 	team, err := h.teamService.GetTeam(r.Context(), teamName)
 	if err != nil {
-		WriteError(w, err)
+		writeError(w, r, err)
 		return
 	}
 
 	WriteJSON(w, http.StatusOK, ToTeamDTO(*team))
 }
 
+// PUT /team/sync
+func (h *Handlers) SyncTeam(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req TeamDTO
+	if err := decodeAndValidate(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.syncTeam(w, r, req)
+}
+
+func (h *Handlers) syncTeam(w http.ResponseWriter, r *http.Request, req TeamDTO) {
+	diff, team, err := h.teamService.SyncTeam(r.Context(), ToTeam(req))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response := TeamSyncResponse{
+		Team: ToTeamDTO(*team),
+		Diff: ToTeamDiffDTO(*diff),
+	}
+	WriteJSON(w, http.StatusOK, response)
+}
+
+// GET /team/list
+func (h *Handlers) ListTeams(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	teams, err := h.teamService.ListTeams(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	teamDTOs := make([]TeamDTO, len(teams))
+	for i, team := range teams {
+		teamDTOs[i] = ToTeamDTO(team)
+	}
+
+	response := TeamListResponse{
+		Teams: teamDTOs,
+	}
+	WriteJSON(w, http.StatusOK, response)
+}
+
+// POST /team/rename
+func (h *Handlers) RenameTeam(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req RenameTeamRequest
+	if err := decodeAndValidate(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.renameTeam(w, r, req.TeamName, req.NewTeamName)
+}
+
+func (h *Handlers) renameTeam(w http.ResponseWriter, r *http.Request, teamName, newTeamName string) {
+	team, err := h.teamService.RenameTeam(r.Context(), teamName, newTeamName)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response := TeamResponse{
+		Team: ToTeamDTO(*team),
+	}
+	WriteJSON(w, http.StatusOK, response)
+}
+
+// POST /team/delete
+func (h *Handlers) DeleteTeam(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req DeleteTeamRequest
+	if err := decodeAndValidate(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.deleteTeam(w, r, req.TeamName, req.ReassignToTeam)
+}
+
+func (h *Handlers) deleteTeam(w http.ResponseWriter, r *http.Request, teamName, reassignTo string) {
+	reassigned, err := h.teamService.DeleteTeam(r.Context(), teamName, reassignTo)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response := DeleteTeamResponse{
+		TeamName:               teamName,
+		ReassignedPullRequests: reassigned,
+	}
+	WriteJSON(w, http.StatusOK, response)
+}
+
 // POST /users/setIsActive
 func (h *Handlers) SetUserActive(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -82,19 +207,76 @@ func (h *Handlers) SetUserActive(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req SetActiveRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteError(w, domain.NewDomainError(domain.ErrorCodeNotFound, "invalid request body"))
+	if err := decodeAndValidate(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.setUserActive(w, r, req.UserID, *req.IsActive)
+}
+
+func (h *Handlers) setUserActive(w http.ResponseWriter, r *http.Request, userID string, isActive bool) {
+	user, err := h.userService.SetActive(r.Context(), userID, isActive)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response := UserResponse{
+		User:      ToUserDTO(*user),
+		UpdatedBy: auth.ActorFromContext(r.Context()),
+	}
+	WriteJSON(w, http.StatusOK, response)
+}
+
+// POST /users/setMaxOpenReviews
+func (h *Handlers) SetUserMaxOpenReviews(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req SetMaxOpenReviewsRequest
+	if err := decodeAndValidate(r, &req); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	user, err := h.userService.SetActive(r.Context(), req.UserID, req.IsActive)
+	user, err := h.userService.SetMaxOpenReviews(r.Context(), req.UserID, *req.MaxOpenReviews)
 	if err != nil {
-		WriteError(w, err)
+		writeError(w, r, err)
 		return
 	}
 
 	response := UserResponse{
-		User: ToUserDTO(*user),
+		User:      ToUserDTO(*user),
+		UpdatedBy: auth.ActorFromContext(r.Context()),
+	}
+	WriteJSON(w, http.StatusOK, response)
+}
+
+// POST /users/setSkills
+func (h *Handlers) SetUserSkills(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req SetSkillsRequest
+	if err := decodeAndValidate(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	user, err := h.userService.SetSkills(r.Context(), req.UserID, *req.Skills)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response := UserResponse{
+		User:      ToUserDTO(*user),
+		UpdatedBy: auth.ActorFromContext(r.Context()),
 	}
 	WriteJSON(w, http.StatusOK, response)
 }
@@ -107,14 +289,23 @@ func (h *Handlers) GetUserReviews(w http.ResponseWriter, r *http.Request) {
 	}
 
 	userID := r.URL.Query().Get("user_id")
-	if userID == "" {
-		WriteError(w, domain.NewDomainError(domain.ErrorCodeNotFound, "user_id parameter is required"))
+	if err := validateID("user_id", userID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.getUserReviews(w, r, userID)
+}
+
+func (h *Handlers) getUserReviews(w http.ResponseWriter, r *http.Request, userID string) {
+	if err := authorizeUser(r, userID); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
 	prs, err := h.pullRequestService.GetPRsByReviewer(r.Context(), userID)
 	if err != nil {
-		WriteError(w, err)
+		writeError(w, r, err)
 		return
 	}
 
@@ -138,16 +329,17 @@ func (h *Handlers) CreatePR(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req CreatePRRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteError(w, domain.NewDomainError(domain.ErrorCodeNotFound, "invalid request body"))
+	if err := decodeAndValidate(r, &req); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	pr, err := h.pullRequestService.CreatePR(r.Context(), req.PullRequestID, req.PullRequestName, req.AuthorID)
+	pr, err := h.pullRequestService.CreatePR(r.Context(), req.PullRequestID, req.PullRequestName, req.AuthorID, req.RequiredSkills)
 	if err != nil {
-		WriteError(w, err)
+		writeError(w, r, err)
 		return
 	}
+	setETag(w, pr.Version)
 
 	response := PullRequestResponse{
 		PR: ToPullRequestDTO(*pr),
@@ -155,6 +347,40 @@ func (h *Handlers) CreatePR(w http.ResponseWriter, r *http.Request) {
 	WriteJSON(w, http.StatusCreated, response)
 }
 
+// GET /pullRequest/get
+func (h *Handlers) GetPR(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	prID := r.URL.Query().Get("pull_request_id")
+	if err := validateID("pull_request_id", prID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.getPR(w, r, prID)
+}
+
+func (h *Handlers) getPR(w http.ResponseWriter, r *http.Request, prID string) {
+	details, err := h.pullRequestService.GetPR(r.Context(), prID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := authorizePullRequest(r, details.PullRequest); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	setETag(w, details.PullRequest.Version)
+
+	response := PullRequestResponse{
+		PR: ToPullRequestDetailsDTO(*details),
+	}
+	WriteJSON(w, http.StatusOK, response)
+}
+
 // POST /pullRequest/merge
 func (h *Handlers) MergePR(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -163,17 +389,28 @@ func (h *Handlers) MergePR(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req MergePRRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteError(w, domain.NewDomainError(domain.ErrorCodeNotFound, "invalid request body"))
+	if err := decodeAndValidate(r, &req); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	pr, err := h.pullRequestService.MergePR(r.Context(), req.PullRequestID)
+	h.mergePR(w, r, req.PullRequestID)
+}
+
+func (h *Handlers) mergePR(w http.ResponseWriter, r *http.Request, prID string) {
+	expectedVersion, err := ifMatchVersion(r)
 	if err != nil {
-		WriteError(w, err)
+		writeError(w, r, err)
 		return
 	}
 
+	pr, err := h.pullRequestService.MergePR(r.Context(), prID, expectedVersion)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	setETag(w, pr.Version)
+
 	response := PullRequestResponse{
 		PR: ToPullRequestDTO(*pr),
 	}
@@ -188,16 +425,27 @@ func (h *Handlers) ReassignReviewer(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req ReassignRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		WriteError(w, domain.NewDomainError(domain.ErrorCodeNotFound, "invalid request body"))
+	if err := decodeAndValidate(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.reassignReviewer(w, r, req.PullRequestID, req.OldUserID)
+}
+
+func (h *Handlers) reassignReviewer(w http.ResponseWriter, r *http.Request, prID, oldUserID string) {
+	expectedVersion, err := ifMatchVersion(r)
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	pr, newReviewerID, err := h.pullRequestService.ReassignReviewer(r.Context(), req.PullRequestID, req.OldUserID)
+	pr, newReviewerID, err := h.pullRequestService.ReassignReviewer(r.Context(), prID, oldUserID, expectedVersion)
 	if err != nil {
-		WriteError(w, err)
+		writeError(w, r, err)
 		return
 	}
+	setETag(w, pr.Version)
 
 	response := ReassignResponse{
 		PR:         ToPullRequestDTO(*pr),
@@ -205,3 +453,104 @@ func (h *Handlers) ReassignReviewer(w http.ResponseWriter, r *http.Request) {
 	}
 	WriteJSON(w, http.StatusOK, response)
 }
+
+// GET /pullRequest/list
+func (h *Handlers) ListPRs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	filter, err := parsePullRequestFilter(r.URL.Query())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := authorizePullRequestFilter(r, filter); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	page, err := h.pullRequestService.ListPRs(r.Context(), filter, r.URL.Query().Get("team_name"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	prDTOs := make([]PullRequestDTO, len(page.Items))
+	for i, pr := range page.Items {
+		prDTOs[i] = ToPullRequestDTO(pr)
+	}
+
+	response := ListPullRequestsResponse{
+		PullRequests: prDTOs,
+		NextCursor:   encodeCursor(page.Next),
+	}
+	WriteJSON(w, http.StatusOK, response)
+}
+
+// POST /roles/assign
+func (h *Handlers) AssignRole(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req RoleAssignmentDTO
+	if err := decodeAndValidate(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.roleService.AssignRole(r.Context(), ToRoleAssignment(req)); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, req)
+}
+
+// POST /roles/revoke
+func (h *Handlers) RevokeRole(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req RoleAssignmentDTO
+	if err := decodeAndValidate(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.roleService.RevokeRole(r.Context(), ToRoleAssignment(req)); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, req)
+}
+
+// GET /roles/list
+func (h *Handlers) ListRoles(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	assignments, err := h.roleService.ListRoles(r.Context(), r.URL.Query().Get("user_id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	roleDTOs := make([]RoleAssignmentDTO, len(assignments))
+	for i, assignment := range assignments {
+		roleDTOs[i] = ToRoleAssignmentDTO(assignment)
+	}
+
+	response := RoleListResponse{
+		Roles: roleDTOs,
+	}
+	WriteJSON(w, http.StatusOK, response)
+}
@@ -0,0 +1,313 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/guverz/pr-reviewer-service/internal/auth"
+	"github.com/guverz/pr-reviewer-service/internal/domain"
+	"github.com/guverz/pr-reviewer-service/internal/service"
+)
+
+// Обработчики /api/v1, которым идентификатор ресурса приходит в пути.
+// Остальные маршруты /api/v1 обслуживаются теми же обработчиками, что и старые маршруты.
+
+const apiV1Prefix = "/api/v1/"
+
+func isV1(r *http.Request) bool {
+	return strings.HasPrefix(r.URL.Path, apiV1Prefix)
+}
+
+// GET /api/v1/teams/{team_name}
+func (h *Handlers) GetTeamV1(w http.ResponseWriter, r *http.Request) {
+	teamName := r.PathValue("team_name")
+	if err := validateTeamName("team_name", teamName); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.getTeam(w, r, teamName)
+}
+
+// PUT /api/v1/teams/{team_name}
+func (h *Handlers) SyncTeamV1(w http.ResponseWriter, r *http.Request) {
+	var req TeamDTO
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	teamName := r.PathValue("team_name")
+	if req.TeamName != "" && req.TeamName != teamName {
+		writeError(w, r, invalidParam("team_name", "does not match path"))
+		return
+	}
+	req.TeamName = teamName
+	if err := req.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.syncTeam(w, r, req)
+}
+
+// PATCH /api/v1/teams/{team_name}
+func (h *Handlers) UpdateTeamV1(w http.ResponseWriter, r *http.Request) {
+	var req UpdateTeamRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	rename := RenameTeamRequest{TeamName: r.PathValue("team_name"), NewTeamName: req.TeamName}
+	if err := rename.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.renameTeam(w, r, rename.TeamName, rename.NewTeamName)
+}
+
+// DELETE /api/v1/teams/{team_name}
+func (h *Handlers) DeleteTeamV1(w http.ResponseWriter, r *http.Request) {
+	req := DeleteTeamRequest{
+		TeamName:       r.PathValue("team_name"),
+		ReassignToTeam: r.URL.Query().Get("reassign_to_team"),
+	}
+	if err := req.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.deleteTeam(w, r, req.TeamName, req.ReassignToTeam)
+}
+
+// GET /api/v1/teams/{team_name}/reviewer-rotation
+func (h *Handlers) GetReviewerRotationV1(w http.ResponseWriter, r *http.Request) {
+	teamName := r.PathValue("team_name")
+	if err := validateTeamName("team_name", teamName); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	rotation, err := h.teamService.GetReviewerRotation(r.Context(), teamName)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, ReviewerRotationResponse{Rotation: ToReviewerRotationDTO(*rotation)})
+}
+
+// PUT /api/v1/teams/{team_name}/reviewer-rotation
+func (h *Handlers) SetReviewerStrategyV1(w http.ResponseWriter, r *http.Request) {
+	teamName := r.PathValue("team_name")
+	if err := validateTeamName("team_name", teamName); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var req SetReviewerStrategyRequest
+	if err := decodeAndValidate(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	rotation, err := h.teamService.SetReviewerStrategy(r.Context(), teamName, domain.ReviewerStrategy(req.Strategy))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, ReviewerRotationResponse{Rotation: ToReviewerRotationDTO(*rotation)})
+}
+
+// PATCH /api/v1/users/{user_id}
+func (h *Handlers) UpdateUserV1(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("user_id")
+	if err := validateID("user_id", userID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var req UpdateUserRequest
+	if err := decodeAndValidate(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	user, err := h.userService.UpdateUser(r.Context(), userID, service.UserUpdate{
+		IsActive:       req.IsActive,
+		MaxOpenReviews: req.MaxOpenReviews,
+		Skills:         req.Skills,
+	})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response := UserResponse{
+		User:      ToUserDTO(*user),
+		UpdatedBy: auth.ActorFromContext(r.Context()),
+	}
+	WriteJSON(w, http.StatusOK, response)
+}
+
+// GET /api/v1/users/{user_id}/reviews
+func (h *Handlers) GetUserReviewsV1(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("user_id")
+	if err := validateID("user_id", userID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.getUserReviews(w, r, userID)
+}
+
+// GET /api/v1/users/{user_id}/unavailability
+func (h *Handlers) ListUnavailabilityV1(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("user_id")
+	if err := validateID("user_id", userID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	windows, err := h.availabilityService.ListUnavailability(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	windowDTOs := make([]UnavailabilityDTO, len(windows))
+	for i, window := range windows {
+		windowDTOs[i] = ToUnavailabilityDTO(window)
+	}
+
+	response := UnavailabilityListResponse{
+		UserID:         userID,
+		Unavailability: windowDTOs,
+	}
+	WriteJSON(w, http.StatusOK, response)
+}
+
+// POST /api/v1/users/{user_id}/unavailability
+func (h *Handlers) AddUnavailabilityV1(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("user_id")
+	if err := validateID("user_id", userID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var req CreateUnavailabilityRequest
+	if err := decodeAndValidate(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	window, err := h.availabilityService.AddUnavailability(r.Context(), ToUnavailability(userID, req))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response := UnavailabilityResponse{
+		Unavailability: ToUnavailabilityDTO(*window),
+	}
+	WriteJSON(w, http.StatusCreated, response)
+}
+
+// DELETE /api/v1/users/{user_id}/unavailability/{unavailability_id}
+func (h *Handlers) DeleteUnavailabilityV1(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("user_id")
+	windowID := r.PathValue("unavailability_id")
+	v := &validator{}
+	v.id("user_id", userID)
+	v.id("unavailability_id", windowID)
+	if err := v.err(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.availabilityService.DeleteUnavailability(r.Context(), userID, windowID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GET /api/v1/pull-requests/{pull_request_id}
+func (h *Handlers) GetPRV1(w http.ResponseWriter, r *http.Request) {
+	prID := r.PathValue("pull_request_id")
+	if err := validateID("pull_request_id", prID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.getPR(w, r, prID)
+}
+
+// POST /api/v1/pull-requests/{pull_request_id}/merge
+func (h *Handlers) MergePRV1(w http.ResponseWriter, r *http.Request) {
+	prID := r.PathValue("pull_request_id")
+	if err := validateID("pull_request_id", prID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.mergePR(w, r, prID)
+}
+
+// POST /api/v1/pull-requests/{pull_request_id}/reassign
+func (h *Handlers) ReassignReviewerV1(w http.ResponseWriter, r *http.Request) {
+	var req ReassignRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	req.PullRequestID = r.PathValue("pull_request_id")
+	if err := req.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	h.reassignReviewer(w, r, req.PullRequestID, req.OldUserID)
+}
+
+// POST /api/v1/roles
+func (h *Handlers) AssignRoleV1(w http.ResponseWriter, r *http.Request) {
+	var req RoleAssignmentDTO
+	if err := decodeAndValidate(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.roleService.AssignRole(r.Context(), ToRoleAssignment(req)); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusCreated, req)
+}
+
+// DELETE /api/v1/roles?user_id=...&role=...&team_name=...
+func (h *Handlers) RevokeRoleV1(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	assignment := RoleAssignmentDTO{
+		UserID:   query.Get("user_id"),
+		Role:     query.Get("role"),
+		TeamName: query.Get("team_name"),
+	}
+	if err := assignment.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.roleService.RevokeRole(r.Context(), ToRoleAssignment(assignment)); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/guverz/pr-reviewer-service/internal/health"
+)
+
+type HealthCheckDTO struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+type ReadinessResponse struct {
+	Status string           `json:"status"`
+	State  string           `json:"state"`
+	Checks []HealthCheckDTO `json:"checks"`
+}
+
+const (
+	healthStatusUp   = "up"
+	healthStatusDown = "down"
+)
+
+// readinessHandler выполняет зарегистрированные проверки и отвечает 200, если сервис готов, и 503 иначе
+func readinessHandler(readiness *health.Readiness) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report := readiness.Check(r.Context())
+
+		resp := ReadinessResponse{
+			Status: healthStatusUp,
+			State:  report.State.String(),
+			Checks: make([]HealthCheckDTO, 0, len(report.Checks)),
+		}
+		for _, check := range report.Checks {
+			dto := HealthCheckDTO{
+				Name:      check.Name,
+				Status:    healthStatusUp,
+				LatencyMs: float64(check.Latency.Microseconds()) / 1000,
+				Error:     check.Error,
+			}
+			if !check.Healthy {
+				dto.Status = healthStatusDown
+			}
+			resp.Checks = append(resp.Checks, dto)
+		}
+
+		statusCode := http.StatusOK
+		if !report.Ready {
+			resp.Status = healthStatusDown
+			statusCode = http.StatusServiceUnavailable
+		}
+		WriteJSON(w, statusCode, resp)
+	}
+}
@@ -0,0 +1,168 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/rs/zerolog"
+
+	"github.com/guverz/pr-reviewer-service/internal/auth"
+	"github.com/guverz/pr-reviewer-service/internal/domain"
+	"github.com/guverz/pr-reviewer-service/internal/service"
+)
+
+const (
+	// IdempotencyKeyHeader — ключ, по которому повтор POST запроса получает сохранённый ответ
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader выставляется в ответах, повторённых из сохранённых
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+	// maxIdempotentBodySize ограничивает тело, которое читается целиком ради хеша запроса
+	maxIdempotentBodySize = 10 << 20
+)
+
+// replayedHeaders — заголовки ответа, которые сохраняются вместе с телом
+var replayedHeaders = []string{"Content-Type", "ETag", "Location"}
+
+// IdempotencyMiddleware выполняет POST запрос с заголовком Idempotency-Key не больше одного раза:
+// ответ сохраняется по ключу и маршруту, повтор с тем же ключом и тем же запросом получает его копию.
+// Ответы 5xx не сохраняются, чтобы после сбоя запрос можно было повторить; то же при панике обработчика.
+func IdempotencyMiddleware(svc *service.IdempotencyService, routes *http.ServeMux, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if r.Method != http.MethodPost || key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if err := validateIdempotencyKey(key); err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentBodySize))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				writeError(w, r, invalidParam("body", "must be at most %d bytes with %s", tooLarge.Limit, IdempotencyKeyHeader))
+				return
+			}
+			writeError(w, r, invalidParam("body", "failed to read request body"))
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := r.Context()
+		route := routeOf(routes, r)
+		record, err := svc.Begin(ctx, key, route, requestHash(r, body))
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		if record != nil {
+			replayResponse(w, record.Response)
+			return
+		}
+
+		// Ключ освобождается, если ответ не сохранён: при 5xx, при ошибке сохранения и при панике
+		// обработчика (defer выполняется и при панике, которая затем продолжает подниматься)
+		stored := false
+		defer func() {
+			if stored {
+				return
+			}
+			if err := svc.Abort(ctx, key, route); err != nil {
+				zerolog.Ctx(ctx).Error().Err(err).Str("idempotency_key", key).Msg("failed to release idempotency key")
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: w}
+		next.ServeHTTP(recorder, r)
+
+		status := recorder.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		if status >= http.StatusInternalServerError {
+			return
+		}
+
+		response := domain.IdempotentResponse{
+			StatusCode: status,
+			Header:     make(map[string]string, len(replayedHeaders)),
+			Body:       recorder.body.Bytes(),
+		}
+		for _, name := range replayedHeaders {
+			if value := w.Header().Get(name); value != "" {
+				response.Header[name] = value
+			}
+		}
+		if err := svc.Complete(ctx, key, route, response); err != nil {
+			zerolog.Ctx(ctx).Error().Err(err).Str("idempotency_key", key).Msg("failed to store idempotent response")
+			return
+		}
+		stored = true
+	})
+}
+
+func validateIdempotencyKey(key string) error {
+	if len(key) > maxIdempotencyKeyLength {
+		return invalidParam(IdempotencyKeyHeader, "must be at most %d characters", maxIdempotencyKeyLength)
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x21 || key[i] > 0x7e {
+			return invalidParam(IdempotencyKeyHeader, "must consist of printable ASCII characters")
+		}
+	}
+	return nil
+}
+
+// requestHash отличает запросы с одним ключом: в него входят путь, query, действующий пользователь и тело
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	for _, part := range []string{r.Method, r.URL.Path, r.URL.RawQuery, auth.ActorFromContext(r.Context())} {
+		io.WriteString(h, part)
+		h.Write([]byte{0})
+	}
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func replayResponse(w http.ResponseWriter, response *domain.IdempotentResponse) {
+	for name, value := range response.Header {
+		w.Header().Set(name, value)
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(response.StatusCode)
+	w.Write(response.Body)
+}
+
+// responseRecorder пишет ответ клиенту и одновременно запоминает статус и тело
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
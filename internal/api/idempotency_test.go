@@ -0,0 +1,62 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guverz/pr-reviewer-service/internal/repository/inmemory"
+	"github.com/guverz/pr-reviewer-service/internal/service"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	svc := service.NewIdempotencyService(inmemory.NewIdempotencyRepository(), time.Hour)
+
+	calls := 0
+	routes := http.NewServeMux()
+	routes.HandleFunc("POST /items", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			panic("handler failed")
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+	handler := IdempotencyMiddleware(svc, routes, routes)
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(body))
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Паника обработчика поднимается дальше, но ключ освобождается
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected the handler panic to propagate")
+			}
+		}()
+		post("{}")
+	}()
+
+	if rec := post("{}"); rec.Code != http.StatusCreated {
+		t.Fatalf("retry after panic: got %d, body %s", rec.Code, rec.Body)
+	}
+	if rec := post("{}"); rec.Code != http.StatusCreated || rec.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Fatalf("replay: got %d, headers %v", rec.Code, rec.Header())
+	}
+	if calls != 2 {
+		t.Fatalf("handler called %d times, want 2", calls)
+	}
+
+	if rec := post(strings.Repeat("x", maxIdempotentBodySize+1)); rec.Code != http.StatusBadRequest {
+		t.Fatalf("oversized body: got %d", rec.Code)
+	}
+	if calls != 2 {
+		t.Fatalf("oversized body reached the handler")
+	}
+}
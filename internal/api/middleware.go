@@ -0,0 +1,272 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/guverz/pr-reviewer-service/internal/auth"
+	"github.com/guverz/pr-reviewer-service/internal/domain"
+	"github.com/guverz/pr-reviewer-service/internal/metrics"
+)
+
+// ActorHeader позволяет админским токенам (и запросам без аутентификации) указать, от чьего имени выполняется изменение
+const ActorHeader = "X-Actor-Id"
+
+// AuthMiddleware аутентифицирует каждый запрос, кроме publicPaths, и кладёт в контекст Identity и действующего пользователя.
+// Пользовательские токены всегда действуют от своего имени, заголовок X-Actor-Id для них игнорируется.
+func AuthMiddleware(authenticator auth.Authenticator, next http.Handler, publicPaths ...string) http.Handler {
+	public := make(map[string]bool, len(publicPaths))
+	for _, path := range publicPaths {
+		public[path] = true
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if public[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		identity, err := authenticator.Authenticate(r)
+		if err != nil {
+			zerolog.Ctx(r.Context()).Warn().Err(err).Msg("authentication failed")
+			w.Header().Set("WWW-Authenticate", `Bearer realm="pr-reviewer-service"`)
+			// Причина (например, ошибка загрузки JWKS) остаётся в логе, клиент видит только фиксированный текст
+			message := auth.ErrInvalidCredentials.Error()
+			if errors.Is(err, auth.ErrMissingCredentials) {
+				message = auth.ErrMissingCredentials.Error()
+			}
+			writeError(w, r, domain.WrapDomainError(err, domain.ErrorCodeUnauthorized, "%s", message))
+			return
+		}
+
+		actorID := identity.UserID
+		if actorID == "" {
+			actorID = r.Header.Get(ActorHeader)
+		}
+
+		ctx := auth.WithIdentity(r.Context(), identity)
+		ctx = auth.WithActor(ctx, actorID)
+		if actorID != "" {
+			zerolog.Ctx(ctx).UpdateContext(func(c zerolog.Context) zerolog.Context {
+				return c.Str("actor", actorID)
+			})
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// adminOnly пропускает только запросы с админским токеном
+func adminOnly(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		identity, ok := auth.FromContext(r.Context())
+		if !ok {
+			writeError(w, r, domain.NewDomainError(domain.ErrorCodeUnauthorized, "authentication required"))
+			return
+		}
+		if !identity.IsAdmin() {
+			writeError(w, r, domain.NewDomainError(domain.ErrorCodeForbidden, "admin token required"))
+			return
+		}
+		next(w, r)
+	}
+}
+
+// authorizeUser проверяет, что вызывающий — сам пользователь userID или администратор
+func authorizeUser(r *http.Request, userID string) error {
+	identity, ok := auth.FromContext(r.Context())
+	if !ok {
+		return domain.NewDomainError(domain.ErrorCodeUnauthorized, "authentication required")
+	}
+	if !identity.CanActAs(userID) {
+		return domain.NewDomainError(domain.ErrorCodeForbidden, "access to another user's reviews is forbidden")
+	}
+	return nil
+}
+
+// authorizePullRequest проверяет, что вызывающий — автор PR, один из его ревьюеров или администратор
+func authorizePullRequest(r *http.Request, pr domain.PullRequest) error {
+	identity, ok := auth.FromContext(r.Context())
+	if !ok {
+		return domain.NewDomainError(domain.ErrorCodeUnauthorized, "authentication required")
+	}
+	if identity.CanActAs(pr.AuthorID) || (identity.UserID != "" && pr.HasReviewer(identity.UserID)) {
+		return nil
+	}
+	return domain.NewDomainError(domain.ErrorCodeForbidden, "access to another user's pull request is forbidden")
+}
+
+// authorizePullRequestFilter проверяет, что пользователь выбирает только свои PR:
+// reviewer_id и author_id должны указывать на него самого, а без них список доступен только администратору
+func authorizePullRequestFilter(r *http.Request, filter domain.PullRequestFilter) error {
+	identity, ok := auth.FromContext(r.Context())
+	if !ok {
+		return domain.NewDomainError(domain.ErrorCodeUnauthorized, "authentication required")
+	}
+	if identity.IsAdmin() {
+		return nil
+	}
+	if filter.ReviewerID == "" && len(filter.AuthorIDs) == 0 {
+		return domain.NewDomainError(domain.ErrorCodeForbidden, "reviewer_id or author_id is required")
+	}
+	if filter.ReviewerID != "" {
+		if err := authorizeUser(r, filter.ReviewerID); err != nil {
+			return err
+		}
+	}
+	for _, authorID := range filter.AuthorIDs {
+		if err := authorizeUser(r, authorID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RequestIDHeader — заголовок с идентификатором запроса; если клиент его не передал, он генерируется
+const RequestIDHeader = "X-Request-Id"
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// LoggingMiddleware кладёт в контекст логгер запроса с request_id и пишет access-лог
+func LoggingMiddleware(logger zerolog.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		requestID := r.Header.Get(RequestIDHeader)
+		if requestID == "" {
+			requestID = newRequestID()
+		}
+		w.Header().Set(RequestIDHeader, requestID)
+
+		ctx := logger.With().Str("request_id", requestID).Logger().WithContext(r.Context())
+		recorder := &statusRecorder{ResponseWriter: w}
+
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+
+		if recorder.status == 0 {
+			recorder.status = http.StatusOK
+		}
+		// Берём логгер из контекста: обработчики могли дополнить его полями (например, actor)
+		zerolog.Ctx(ctx).Info().
+			Str("method", r.Method).
+			Str("path", r.URL.Path).
+			Int("status", recorder.status).
+			Dur("latency", time.Since(start)).
+			Msg("http request")
+	})
+}
+
+// unmatchedRoute — метка маршрута для запросов, не попавших ни в один зарегистрированный шаблон
+const unmatchedRoute = "unmatched"
+
+// MetricsMiddleware считает запросы и их длительность по методу, маршруту и статусу.
+// Маршрут берётся из шаблона, под который запрос попадает в routes, а не из пути,
+// чтобы число значений метки не росло от произвольных URL.
+func MetricsMiddleware(m *metrics.Metrics, routes *http.ServeMux, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		route := routeOf(routes, r)
+
+		recorder := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(recorder, r)
+
+		if recorder.status == 0 {
+			recorder.status = http.StatusOK
+		}
+		m.ObserveHTTP(r.Method, route, recorder.status, time.Since(start))
+	})
+}
+
+// TracingMiddleware открывает серверный спан на каждый запрос, продолжая трейс из заголовков
+// W3C traceparent/tracestate, и добавляет trace_id в логгер запроса
+func TracingMiddleware(routes *http.ServeMux, next http.Handler) http.Handler {
+	tracer := otel.Tracer("github.com/guverz/pr-reviewer-service/internal/api")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		route := routeOf(routes, r)
+
+		ctx, span := tracer.Start(ctx, r.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(r.Method),
+				semconv.HTTPRoute(route),
+				semconv.URLPath(r.URL.Path),
+			),
+		)
+		defer span.End()
+
+		if spanCtx := span.SpanContext(); spanCtx.IsValid() {
+			zerolog.Ctx(ctx).UpdateContext(func(c zerolog.Context) zerolog.Context {
+				return c.Str("trace_id", spanCtx.TraceID().String())
+			})
+		}
+
+		recorder := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+
+		if recorder.status == 0 {
+			recorder.status = http.StatusOK
+		}
+		span.SetAttributes(semconv.HTTPResponseStatusCode(recorder.status))
+		if recorder.status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(recorder.status))
+		}
+	})
+}
+
+// routeOf возвращает шаблон маршрута (без метода), под который попадает запрос, или unmatchedRoute
+func routeOf(routes *http.ServeMux, r *http.Request) string {
+	_, pattern := routes.Handler(r)
+	if pattern == "" {
+		return unmatchedRoute
+	}
+	if _, path, ok := strings.Cut(pattern, " "); ok {
+		return path
+	}
+	return pattern
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(b)
+}
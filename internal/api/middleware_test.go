@@ -0,0 +1,63 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/guverz/pr-reviewer-service/internal/auth"
+)
+
+// failingAuthenticator отклоняет любой запрос с заданной ошибкой
+type failingAuthenticator struct {
+	err error
+}
+
+func (a failingAuthenticator) Authenticate(*http.Request) (auth.Identity, error) {
+	return auth.Identity{}, a.err
+}
+
+func TestAuthMiddlewareHidesErrorCause(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("handler must not be called")
+	})
+
+	tests := []struct {
+		name    string
+		err     error
+		message string
+	}{
+		{
+			name:    "jwks fetch failure",
+			err:     fmt.Errorf("fetch jwks: Get \"https://idp.internal/keys\": dial tcp: timeout: %w", auth.ErrInvalidCredentials),
+			message: "invalid bearer token",
+		},
+		{
+			name:    "unwrapped internal error",
+			err:     errors.New(`parse jwk "kid-1": bad exponent`),
+			message: "invalid bearer token",
+		},
+		{
+			name:    "missing token",
+			err:     auth.ErrMissingCredentials,
+			message: "missing bearer token",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			AuthMiddleware(failingAuthenticator{err: tt.err}, next).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/team/list", nil))
+
+			body := rec.Body.String()
+			if rec.Code != http.StatusUnauthorized || !strings.Contains(body, tt.message) {
+				t.Fatalf("got %d %s, want 401 with %q", rec.Code, body, tt.message)
+			}
+			if strings.Contains(body, "jwk") {
+				t.Fatalf("response leaks the error cause: %s", body)
+			}
+		})
+	}
+}
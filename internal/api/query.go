@@ -0,0 +1,136 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/guverz/pr-reviewer-service/internal/domain"
+)
+
+type cursorPayload struct {
+	SortBy    string `json:"s"`
+	Order     string `json:"o"`
+	SortValue int64  `json:"v"`
+	ID        string `json:"id"`
+}
+
+// encodeCursor превращает курсор в непрозрачную для клиента строку
+func encodeCursor(c *domain.PageCursor) *string {
+	if c == nil {
+		return nil
+	}
+	raw, _ := json.Marshal(cursorPayload{
+		SortBy:    string(c.SortBy),
+		Order:     string(c.Order),
+		SortValue: c.SortValue,
+		ID:        c.ID,
+	})
+	encoded := base64.RawURLEncoding.EncodeToString(raw)
+	return &encoded
+}
+
+func decodeCursor(s string) (*domain.PageCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	var payload cursorPayload
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, err
+	}
+	return &domain.PageCursor{
+		SortBy:    domain.PullRequestSortField(payload.SortBy),
+		Order:     domain.SortOrder(payload.Order),
+		SortValue: payload.SortValue,
+		ID:        payload.ID,
+	}, nil
+}
+
+// parsePullRequestFilter собирает фильтр из query-параметров /pullRequest/list
+func parsePullRequestFilter(q url.Values) (domain.PullRequestFilter, error) {
+	var filter domain.PullRequestFilter
+
+	if v := q.Get("status"); v != "" {
+		status := domain.PullRequestStatus(v)
+		if !status.IsValid() {
+			return filter, invalidParam("status", "must be one of: %s, %s", domain.PullRequestStatusOpen, domain.PullRequestStatusMerged)
+		}
+		filter.Status = &status
+	}
+
+	if v := q.Get("author_id"); v != "" {
+		if err := validateID("author_id", v); err != nil {
+			return filter, err
+		}
+		filter.AuthorIDs = []string{v}
+	}
+	if v := q.Get("reviewer_id"); v != "" {
+		if err := validateID("reviewer_id", v); err != nil {
+			return filter, err
+		}
+		filter.ReviewerID = v
+	}
+
+	if v := q.Get("need_more_reviewers"); v != "" {
+		needMore, err := strconv.ParseBool(v)
+		if err != nil {
+			return filter, invalidParam("need_more_reviewers", "must be a boolean")
+		}
+		filter.NeedMoreReviewers = &needMore
+	}
+
+	timeParams := []struct {
+		name string
+		dst  **time.Time
+	}{
+		{"created_from", &filter.CreatedFrom},
+		{"created_to", &filter.CreatedTo},
+		{"merged_from", &filter.MergedFrom},
+		{"merged_to", &filter.MergedTo},
+	}
+	for _, p := range timeParams {
+		v := q.Get(p.name)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return filter, invalidParam(p.name, "must be an RFC3339 timestamp")
+		}
+		*p.dst = &t
+	}
+
+	if v := q.Get("sort_by"); v != "" {
+		filter.SortBy = domain.PullRequestSortField(v)
+		if !filter.SortBy.IsValid() {
+			return filter, invalidParam("sort_by", "must be one of: %s, %s, %s", domain.PullRequestSortByCreatedAt, domain.PullRequestSortByMergedAt, domain.PullRequestSortByID)
+		}
+	}
+	if v := q.Get("order"); v != "" {
+		filter.Order = domain.SortOrder(v)
+		if !filter.Order.IsValid() {
+			return filter, invalidParam("order", "must be one of: %s, %s", domain.SortOrderAsc, domain.SortOrderDesc)
+		}
+	}
+
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			return filter, invalidParam("limit", "must be a positive integer")
+		}
+		filter.Limit = limit
+	}
+
+	if v := q.Get("cursor"); v != "" {
+		cursor, err := decodeCursor(v)
+		if err != nil {
+			return filter, invalidParam("cursor", "is malformed")
+		}
+		filter.After = cursor
+	}
+
+	return filter, nil
+}
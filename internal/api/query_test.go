@@ -0,0 +1,43 @@
+package api
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/guverz/pr-reviewer-service/internal/domain"
+)
+
+func TestDecodeCursor(t *testing.T) {
+	cursor := &domain.PageCursor{SortBy: domain.PullRequestSortByCreatedAt, Order: domain.SortOrderAsc, SortValue: -1, ID: "pr-1"}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    *domain.PageCursor
+		wantErr bool
+	}{
+		{name: "round trip", input: *encodeCursor(cursor), want: cursor},
+		{name: "not base64", input: "%%%", wantErr: true},
+		{name: "padded base64", input: base64.URLEncoding.EncodeToString([]byte("{}")), wantErr: true},
+		{name: "not json", input: base64.RawURLEncoding.EncodeToString([]byte("pr-1")), wantErr: true},
+		{name: "empty payload", input: base64.RawURLEncoding.EncodeToString([]byte("{}")), want: &domain.PageCursor{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeCursor(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if *got != *tt.want {
+				t.Errorf("got %+v, want %+v", *got, *tt.want)
+			}
+		})
+	}
+}
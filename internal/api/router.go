@@ -3,6 +3,7 @@ package api
 import (
 	"net/http"
 
+	"github.com/guverz/pr-reviewer-service/internal/health"
 	"github.com/guverz/pr-reviewer-service/internal/service"
 )
 
@@ -10,29 +11,96 @@ func NewRouter(
 	teamService *service.TeamService,
 	userService *service.UserService,
 	pullRequestService *service.PullRequestService,
-) http.Handler {
+	adminService *service.AdminService,
+	roleService *service.RoleService,
+	availabilityService *service.AvailabilityService,
+	metricsHandler http.Handler,
+	readiness *health.Readiness,
+) *http.ServeMux {
 	mux := http.NewServeMux()
 
-	handlers := NewHandlers(teamService, userService, pullRequestService)
+	handlers := NewHandlers(teamService, userService, pullRequestService, adminService, roleService, availabilityService)
 
 	// Teams endpoints
-	mux.HandleFunc("/team/add", handlers.AddTeam)
+	mux.HandleFunc("/team/add", adminOnly(handlers.AddTeam))
 	mux.HandleFunc("/team/get", handlers.GetTeam)
+	mux.HandleFunc("/team/sync", adminOnly(handlers.SyncTeam))
+	mux.HandleFunc("/team/list", handlers.ListTeams)
+	mux.HandleFunc("/team/rename", adminOnly(handlers.RenameTeam))
+	mux.HandleFunc("/team/delete", adminOnly(handlers.DeleteTeam))
 
 	// Users endpoints
 	mux.HandleFunc("/users/setIsActive", handlers.SetUserActive)
+	mux.HandleFunc("/users/setMaxOpenReviews", handlers.SetUserMaxOpenReviews)
+	mux.HandleFunc("/users/setSkills", handlers.SetUserSkills)
 	mux.HandleFunc("/users/getReview", handlers.GetUserReviews)
 
 	// PullRequests endpoints
 	mux.HandleFunc("/pullRequest/create", handlers.CreatePR)
+	mux.HandleFunc("/pullRequest/get", handlers.GetPR)
 	mux.HandleFunc("/pullRequest/merge", handlers.MergePR)
 	mux.HandleFunc("/pullRequest/reassign", handlers.ReassignReviewer)
-	
+	mux.HandleFunc("/pullRequest/list", handlers.ListPRs)
+
+	// Admin endpoints
+	mux.HandleFunc("/admin/export", adminOnly(handlers.Export))
+	mux.HandleFunc("/admin/import", adminOnly(handlers.Import))
+
+	// Roles endpoints
+	mux.HandleFunc("/roles/assign", handlers.AssignRole)
+	mux.HandleFunc("/roles/revoke", handlers.RevokeRole)
+	mux.HandleFunc("/roles/list", handlers.ListRoles)
+
+	registerV1(mux, handlers)
+
+	// Prometheus metrics
+	mux.Handle("/metrics", metricsHandler)
+
 	// Health check
 	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("OK"))
 	})
-	
+
+	// Readiness: состояние сервера и зарегистрированные проверки зависимостей
+	mux.HandleFunc("/readyz", readinessHandler(readiness))
+
 	return mux
 }
+
+// registerV1 регистрирует ресурсные маршруты /api/v1. Старые маршруты выше остаются
+// совместимыми псевдонимами и обслуживаются теми же Handlers.
+func registerV1(mux *http.ServeMux, handlers *Handlers) {
+	// Teams
+	mux.HandleFunc("GET /api/v1/teams", handlers.ListTeams)
+	mux.HandleFunc("POST /api/v1/teams", adminOnly(handlers.AddTeam))
+	mux.HandleFunc("GET /api/v1/teams/{team_name}", handlers.GetTeamV1)
+	mux.HandleFunc("PUT /api/v1/teams/{team_name}", adminOnly(handlers.SyncTeamV1))
+	mux.HandleFunc("PATCH /api/v1/teams/{team_name}", adminOnly(handlers.UpdateTeamV1))
+	mux.HandleFunc("DELETE /api/v1/teams/{team_name}", adminOnly(handlers.DeleteTeamV1))
+	mux.HandleFunc("GET /api/v1/teams/{team_name}/reviewer-rotation", handlers.GetReviewerRotationV1)
+	mux.HandleFunc("PUT /api/v1/teams/{team_name}/reviewer-rotation", handlers.SetReviewerStrategyV1)
+
+	// Users
+	mux.HandleFunc("PATCH /api/v1/users/{user_id}", handlers.UpdateUserV1)
+	mux.HandleFunc("GET /api/v1/users/{user_id}/reviews", handlers.GetUserReviewsV1)
+	mux.HandleFunc("GET /api/v1/users/{user_id}/unavailability", handlers.ListUnavailabilityV1)
+	mux.HandleFunc("POST /api/v1/users/{user_id}/unavailability", handlers.AddUnavailabilityV1)
+	mux.HandleFunc("DELETE /api/v1/users/{user_id}/unavailability/{unavailability_id}", handlers.DeleteUnavailabilityV1)
+
+	// Pull requests
+	mux.HandleFunc("GET /api/v1/pull-requests", handlers.ListPRs)
+	mux.HandleFunc("POST /api/v1/pull-requests", handlers.CreatePR)
+	mux.HandleFunc("GET /api/v1/pull-requests/{pull_request_id}", handlers.GetPRV1)
+	mux.HandleFunc("POST /api/v1/pull-requests/{pull_request_id}/merge", handlers.MergePRV1)
+	mux.HandleFunc("POST /api/v1/pull-requests/{pull_request_id}/reassign", handlers.ReassignReviewerV1)
+
+	// Admin
+	mux.HandleFunc("GET /api/v1/admin/export", adminOnly(handlers.Export))
+	mux.HandleFunc("POST /api/v1/admin/import", adminOnly(handlers.Import))
+
+	// Roles
+	mux.HandleFunc("GET /api/v1/roles", handlers.ListRoles)
+	mux.HandleFunc("POST /api/v1/roles", handlers.AssignRoleV1)
+	mux.HandleFunc("DELETE /api/v1/roles", handlers.RevokeRoleV1)
+}
@@ -0,0 +1,344 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/guverz/pr-reviewer-service/internal/domain"
+)
+
+const (
+	maxIDLength       = 64
+	maxTeamNameLength = 100
+	maxNameLength     = 255
+	maxSkillLength    = 32
+	maxSkills         = 20
+)
+
+// Идентификаторы пользователей и PR попадают в пути /api/v1, поэтому набор символов ограничен
+var idPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._:-]*$`)
+
+// Теги экспертизы вроде go, c++, c#, node.js, ci-cd; регистр не важен
+var skillPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9+#._-]*$`)
+
+// validator собирает нарушения по всем полям, чтобы клиент увидел их сразу, а не по одному
+type validator struct {
+	violations []domain.FieldViolation
+}
+
+func (v *validator) add(field, format string, args ...any) {
+	v.violations = append(v.violations, domain.FieldViolation{
+		Field:   field,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) err() error {
+	if len(v.violations) == 0 {
+		return nil
+	}
+	return domain.NewValidationError(v.violations...)
+}
+
+// id проверяет обязательный идентификатор пользователя или PR
+func (v *validator) id(field, value string) {
+	switch {
+	case value == "":
+		v.add(field, "is required")
+	case len(value) > maxIDLength:
+		v.add(field, "must be at most %d characters", maxIDLength)
+	case !idPattern.MatchString(value):
+		v.add(field, "must start with a letter or digit and contain only letters, digits, '.', '_', ':' and '-'")
+	}
+}
+
+// teamName проверяет обязательное имя команды
+func (v *validator) teamName(field, value string) {
+	if value == "" {
+		v.add(field, "is required")
+		return
+	}
+	if strings.Contains(value, "/") {
+		v.add(field, "must not contain '/'")
+	}
+	v.text(field, value, maxTeamNameLength)
+}
+
+// name проверяет обязательное человекочитаемое имя (пользователя, PR)
+func (v *validator) name(field, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.add(field, "is required")
+		return
+	}
+	v.text(field, value, maxNameLength)
+}
+
+func (v *validator) text(field, value string, maxLength int) {
+	if !utf8.ValidString(value) {
+		v.add(field, "must be valid UTF-8")
+		return
+	}
+	if utf8.RuneCountInString(value) > maxLength {
+		v.add(field, "must be at most %d characters", maxLength)
+	}
+	if strings.TrimSpace(value) != value {
+		v.add(field, "must not have leading or trailing spaces")
+	}
+	if strings.IndexFunc(value, unicode.IsControl) >= 0 {
+		v.add(field, "must not contain control characters")
+	}
+}
+
+func (v *validator) members(field string, members []TeamMemberDTO) {
+	seen := make(map[string]int, len(members))
+	for i, member := range members {
+		prefix := fmt.Sprintf("%s[%d]", field, i)
+		v.id(prefix+".user_id", member.UserID)
+		v.name(prefix+".username", member.Username)
+		if first, ok := seen[member.UserID]; ok && member.UserID != "" {
+			v.add(prefix+".user_id", "duplicates %s[%d].user_id", field, first)
+			continue
+		}
+		seen[member.UserID] = i
+	}
+}
+
+func (v *validator) role(field, value string) {
+	if value == "" {
+		v.add(field, "is required")
+		return
+	}
+	if !domain.Role(value).IsValid() {
+		v.add(field, "must be one of: %s, %s", domain.RoleAdmin, domain.RoleTeamLead)
+	}
+}
+
+func (v *validator) maxOpenReviews(field string, value int) {
+	if value < 0 {
+		v.add(field, "must not be negative (0 removes the limit)")
+	}
+}
+
+func (v *validator) skills(field string, skills []string) {
+	if len(skills) > maxSkills {
+		v.add(field, "must contain at most %d tags", maxSkills)
+	}
+	for i, skill := range skills {
+		itemField := fmt.Sprintf("%s[%d]", field, i)
+		switch {
+		case skill == "":
+			v.add(itemField, "must not be empty")
+		case len(skill) > maxSkillLength:
+			v.add(itemField, "must be at most %d characters", maxSkillLength)
+		case !skillPattern.MatchString(skill):
+			v.add(itemField, "must start with a letter or digit and contain only letters, digits, '+', '#', '.', '_' and '-'")
+		}
+	}
+}
+
+func (v *validator) reviewerStrategy(field, value string) {
+	if value == "" {
+		v.add(field, "is required")
+		return
+	}
+	if !domain.ReviewerStrategy(value).IsValid() {
+		v.add(field, "must be one of: %s, %s", domain.ReviewerStrategyRandom, domain.ReviewerStrategyRoundRobin)
+	}
+}
+
+func (v *validator) unavailabilityReason(field, value string) {
+	if value == "" {
+		v.add(field, "is required")
+		return
+	}
+	if !domain.UnavailabilityReason(value).IsValid() {
+		v.add(field, "must be one of: %s, %s, %s, %s", domain.UnavailabilityReasonVacation,
+			domain.UnavailabilityReasonOnCall, domain.UnavailabilityReasonPartTime, domain.UnavailabilityReasonOther)
+	}
+}
+
+// timestamp проверяет обязательное время в формате RFC 3339; ok=false, если значение не разобрано
+func (v *validator) timestamp(field, value string) (t time.Time, ok bool) {
+	if value == "" {
+		v.add(field, "is required")
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		v.add(field, "must be an RFC 3339 timestamp, e.g. 2025-11-03T09:00:00Z")
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// invalidParam — ошибка для одного некорректного query-параметра
+func invalidParam(name, format string, args ...any) error {
+	v := &validator{}
+	v.add(name, format, args...)
+	return v.err()
+}
+
+// validateID проверяет идентификатор из query-параметра или пути
+func validateID(field, value string) error {
+	v := &validator{}
+	v.id(field, value)
+	return v.err()
+}
+
+func validateTeamName(field, value string) error {
+	v := &validator{}
+	v.teamName(field, value)
+	return v.err()
+}
+
+type validatable interface {
+	Validate() error
+}
+
+// decodeJSON разбирает тело запроса в dst; некорректный JSON — это VALIDATION_ERROR по полю body
+func decodeJSON(r *http.Request, dst any) error {
+	if err := json.NewDecoder(r.Body).Decode(dst); err != nil {
+		return invalidParam("body", "invalid JSON: %v", err)
+	}
+	return nil
+}
+
+// decodeAndValidate разбирает тело запроса и проверяет его правилами DTO
+func decodeAndValidate(r *http.Request, dst validatable) error {
+	if err := decodeJSON(r, dst); err != nil {
+		return err
+	}
+	return dst.Validate()
+}
+
+func (d TeamDTO) Validate() error {
+	v := &validator{}
+	v.teamName("team_name", d.TeamName)
+	v.members("members", d.Members)
+	return v.err()
+}
+
+func (d RenameTeamRequest) Validate() error {
+	v := &validator{}
+	v.teamName("team_name", d.TeamName)
+	v.teamName("new_team_name", d.NewTeamName)
+	return v.err()
+}
+
+func (d UpdateTeamRequest) Validate() error {
+	v := &validator{}
+	v.teamName("team_name", d.TeamName)
+	return v.err()
+}
+
+func (d DeleteTeamRequest) Validate() error {
+	v := &validator{}
+	v.teamName("team_name", d.TeamName)
+	if d.ReassignToTeam != "" {
+		v.teamName("reassign_to_team", d.ReassignToTeam)
+	}
+	return v.err()
+}
+
+func (d SetActiveRequest) Validate() error {
+	v := &validator{}
+	v.id("user_id", d.UserID)
+	if d.IsActive == nil {
+		v.add("is_active", "is required")
+	}
+	return v.err()
+}
+
+func (d SetMaxOpenReviewsRequest) Validate() error {
+	v := &validator{}
+	v.id("user_id", d.UserID)
+	if d.MaxOpenReviews == nil {
+		v.add("max_open_reviews", "is required")
+	} else {
+		v.maxOpenReviews("max_open_reviews", *d.MaxOpenReviews)
+	}
+	return v.err()
+}
+
+func (d SetSkillsRequest) Validate() error {
+	v := &validator{}
+	v.id("user_id", d.UserID)
+	if d.Skills == nil {
+		v.add("skills", "is required")
+	} else {
+		v.skills("skills", *d.Skills)
+	}
+	return v.err()
+}
+
+func (d UpdateUserRequest) Validate() error {
+	v := &validator{}
+	if d.IsActive == nil && d.MaxOpenReviews == nil && d.Skills == nil {
+		v.add("body", "at least one of is_active, max_open_reviews, skills is required")
+	}
+	if d.MaxOpenReviews != nil {
+		v.maxOpenReviews("max_open_reviews", *d.MaxOpenReviews)
+	}
+	if d.Skills != nil {
+		v.skills("skills", *d.Skills)
+	}
+	return v.err()
+}
+
+func (d CreatePRRequest) Validate() error {
+	v := &validator{}
+	v.id("pull_request_id", d.PullRequestID)
+	v.name("pull_request_name", d.PullRequestName)
+	v.id("author_id", d.AuthorID)
+	v.skills("required_skills", d.RequiredSkills)
+	return v.err()
+}
+
+func (d MergePRRequest) Validate() error {
+	v := &validator{}
+	v.id("pull_request_id", d.PullRequestID)
+	return v.err()
+}
+
+func (d ReassignRequest) Validate() error {
+	v := &validator{}
+	v.id("pull_request_id", d.PullRequestID)
+	v.id("old_user_id", d.OldUserID)
+	return v.err()
+}
+
+func (d RoleAssignmentDTO) Validate() error {
+	v := &validator{}
+	v.id("user_id", d.UserID)
+	v.role("role", d.Role)
+	switch {
+	case d.TeamName != "":
+		v.teamName("team_name", d.TeamName)
+	case domain.Role(d.Role).IsTeamScoped():
+		v.add("team_name", "is required for role %s", d.Role)
+	}
+	return v.err()
+}
+
+func (d SetReviewerStrategyRequest) Validate() error {
+	v := &validator{}
+	v.reviewerStrategy("strategy", d.Strategy)
+	return v.err()
+}
+
+func (d CreateUnavailabilityRequest) Validate() error {
+	v := &validator{}
+	v.unavailabilityReason("reason", d.Reason)
+	startsAt, startsOK := v.timestamp("starts_at", d.StartsAt)
+	endsAt, endsOK := v.timestamp("ends_at", d.EndsAt)
+	if startsOK && endsOK && !endsAt.After(startsAt) {
+		v.add("ends_at", "must be after starts_at")
+	}
+	return v.err()
+}
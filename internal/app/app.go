@@ -2,18 +2,61 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
 
 	"github.com/guverz/pr-reviewer-service/internal/api"
+	"github.com/guverz/pr-reviewer-service/internal/auth"
 	"github.com/guverz/pr-reviewer-service/internal/config"
+	"github.com/guverz/pr-reviewer-service/internal/health"
 	"github.com/guverz/pr-reviewer-service/internal/httpserver"
+	"github.com/guverz/pr-reviewer-service/internal/metrics"
 	"github.com/guverz/pr-reviewer-service/internal/repository/inmemory"
+	"github.com/guverz/pr-reviewer-service/internal/repository/instrumented"
 	"github.com/guverz/pr-reviewer-service/internal/service"
+	"github.com/guverz/pr-reviewer-service/pkg/logger"
+	"github.com/guverz/pr-reviewer-service/pkg/tracing"
 )
 
 type Application struct {
 	cfg    *config.Config
+	logger zerolog.Logger
 	server *httpserver.Server
+	// workers работают в фоне, пока обслуживаются запросы
+	workers []worker
+	// closers выполняются по порядку после остановки HTTP сервера
+	closers []closer
+}
+
+// worker — периодическая фоновая задача; ошибка одного запуска логируется и не останавливает задачу
+type worker struct {
+	name     string
+	interval time.Duration
+	run      func(ctx context.Context) error
+	// heartbeat отмечается после каждого успешного запуска и проверяется в /readyz
+	heartbeat *health.Heartbeat
+}
+
+// workerHeartbeatIntervals — сколько интервалов воркер может пропустить или провалить подряд,
+// прежде чем /readyz сочтёт его неработающим
+const workerHeartbeatIntervals = 3
+
+func newWorker(name string, interval time.Duration, run func(ctx context.Context) error) worker {
+	return worker{
+		name:      name,
+		interval:  interval,
+		run:       run,
+		heartbeat: health.NewHeartbeat(workerHeartbeatIntervals * interval),
+	}
+}
+
+type closer struct {
+	name  string
+	close func(ctx context.Context) error
 }
 
 func New(opts ...Option) (*Application, error) {
@@ -22,27 +65,100 @@ func New(opts ...Option) (*Application, error) {
 		return nil, fmt.Errorf("load config: %w", err)
 	}
 
-	// Инициализируем репозитории
+	log, err := logger.New(cfg.Log.Level, cfg.Log.Format)
+	if err != nil {
+		return nil, fmt.Errorf("init logger: %w", err)
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:     cfg.Tracing.Exporter,
+		File:         cfg.Tracing.File,
+		OTLPEndpoint: cfg.Tracing.OTLPEndpoint,
+		OTLPInsecure: cfg.Tracing.OTLPInsecure,
+		SampleRatio:  cfg.Tracing.SampleRatio,
+		ServiceName:  cfg.Tracing.ServiceName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("init tracing: %w", err)
+	}
+
+	m := metrics.New()
+
+	// Инициализируем репозитории; каждая операция хранилища замеряется
 	repos := inmemory.NewRepositories()
+	repos.Team = instrumented.NewTeamRepository(repos.Team, m)
+	repos.User = instrumented.NewUserRepository(repos.User, m)
+	repos.PullRequest = instrumented.NewPullRequestRepository(repos.PullRequest, m)
+	repos.Role = instrumented.NewRoleRepository(repos.Role, m)
+	repos.Availability = instrumented.NewAvailabilityRepository(repos.Availability, m)
+	repos.Rotation = instrumented.NewRotationRepository(repos.Rotation, m)
+	repos.Idempotency = instrumented.NewIdempotencyRepository(repos.Idempotency, m)
+	repos.Transaction = instrumented.NewTransactionManager(repos.Transaction, m)
+	m.MustRegister(metrics.NewReviewCollector(repos.PullRequest))
+
+	// Проверки готовности; до запуска сервера /readyz отвечает not-ready
+	readiness := health.NewReadiness(cfg.Health.CheckTimeout)
+	readiness.Register("storage", health.CheckerFunc(func(ctx context.Context) error {
+		_, err := repos.Team.List(ctx)
+		return err
+	}))
 
 	// Инициализируем сервисы
-	reviewerSelector := service.NewReviewerSelector()
-	teamService := service.NewTeamService(repos.Team, repos.User, repos.Transaction)
-	userService := service.NewUserService(repos.User, repos.Team)
-	pullRequestService := service.NewPullRequestService(repos.PullRequest, repos.User, reviewerSelector)
+	reviewerSelector := service.NewReviewerSelector(repos.PullRequest, repos.Availability, repos.Rotation)
+	authorizer := service.NewAuthorizer(repos.Role)
+	teamService := service.NewTeamService(repos.Team, repos.User, repos.PullRequest, repos.Role, repos.Rotation, repos.Transaction, reviewerSelector, authorizer, m)
+	userService := service.NewUserService(repos.User, repos.Team, repos.Transaction, authorizer)
+	pullRequestService := service.NewPullRequestService(repos.PullRequest, repos.User, repos.Transaction, reviewerSelector, authorizer, m)
+	adminService := service.NewAdminService(repos.Team, repos.User, repos.PullRequest, repos.Role, repos.Availability, repos.Rotation, repos.Transaction, authorizer)
+	roleService := service.NewRoleService(repos.Role, repos.User, repos.Team, authorizer)
+	availabilityService := service.NewAvailabilityService(repos.Availability, repos.User, repos.PullRequest, pullRequestService, authorizer)
+	idempotencyService := service.NewIdempotencyService(repos.Idempotency, cfg.Idempotency.TTL)
 
 	// Создаём роутер
-	router := api.NewRouter(teamService, userService, pullRequestService)
+	router := api.NewRouter(teamService, userService, pullRequestService, adminService, roleService, availabilityService, m.Handler(), readiness)
+
+	// Аутентификация перед роутером
+	authenticator, err := newAuthenticator(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("init authenticator: %w", err)
+	}
+	// Идемпотентность после аутентификации: действующий пользователь входит в хеш запроса
+	handler := api.IdempotencyMiddleware(idempotencyService, router, router)
+	handler = api.AuthMiddleware(authenticator, handler, "/healthz", "/readyz", "/metrics")
+	handler = api.TracingMiddleware(router, handler)
+	handler = api.MetricsMiddleware(m, router, handler)
+	handler = api.LoggingMiddleware(log, handler)
 
 	// Инициализируем HTTP сервер
-	server, err := httpserver.New(cfg, router)
+	server, err := httpserver.New(cfg, handler, readiness)
 	if err != nil {
 		return nil, fmt.Errorf("init http server: %w", err)
 	}
 
 	app := &Application{
 		cfg:    cfg,
+		logger: log,
 		server: server,
+		workers: []worker{
+			newWorker("idempotency_cleanup", cfg.Idempotency.CleanupInterval, func(ctx context.Context) error {
+				_, err := idempotencyService.DeleteExpired(ctx)
+				return err
+			}),
+			newWorker("unavailability_reassign", cfg.Availability.ReassignInterval, func(ctx context.Context) error {
+				_, err := availabilityService.ReassignUnavailableReviews(ctx)
+				return err
+			}),
+		},
+		closers: []closer{
+			// Сначала выгружаем накопленные спаны, затем закрываем хранилище
+			{name: "tracing", close: shutdownTracing},
+			{name: "storage", close: repos.Close},
+		},
+	}
+
+	// Зависший воркер делает сервис неготовым
+	for _, wk := range app.workers {
+		readiness.Register("worker:"+wk.name, wk.heartbeat)
 	}
 
 	for _, opt := range opts {
@@ -52,9 +168,114 @@ func New(opts ...Option) (*Application, error) {
 	return app, nil
 }
 
-func (a *Application) Run() error {
-	ctx, cancel := context.WithCancel(context.Background())
+// Run обслуживает запросы до отмены ctx. После отмены сервер перестаёт принимать соединения
+// и дожидается текущих запросов, затем выполняются closers. Всё это укладывается
+// в HTTP_SHUTDOWN_TIMEOUT, отсчитываемый от момента отмены ctx.
+func (a *Application) Run(ctx context.Context) error {
+	stopping := make(chan time.Time, 1)
+	stopWatching := context.AfterFunc(ctx, func() {
+		stopping <- time.Now()
+		a.logger.Info().Dur("timeout", a.cfg.HTTP.ShutdownTimeout).Msg("shutting down")
+	})
+	defer stopWatching()
+
+	workersCtx, stopWorkers := context.WithCancel(ctx)
+	var workers sync.WaitGroup
+	for _, wk := range a.workers {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			a.runWorker(workersCtx, wk)
+		}()
+	}
+
+	a.logger.Info().Str("addr", a.cfg.HTTP.Addr).Msg("starting http server")
+	serveErr := a.server.Serve(ctx)
+
+	// Фоновые задачи останавливаем раньше closers: они пользуются хранилищем
+	stopWorkers()
+	workers.Wait()
+
+	stoppedAt := time.Now()
+	select {
+	case stoppedAt = <-stopping:
+		a.logger.Info().Msg("http server stopped")
+	default:
+	}
+
+	shutdownCtx, cancel := context.WithDeadline(context.Background(), stoppedAt.Add(a.cfg.HTTP.ShutdownTimeout))
 	defer cancel()
 
-	return a.server.Serve(ctx)
+	errs := []error{serveErr}
+	for _, c := range a.closers {
+		if err := c.close(shutdownCtx); err != nil {
+			a.logger.Error().Err(err).Str("component", c.name).Msg("shutdown failed")
+			errs = append(errs, fmt.Errorf("close %s: %w", c.name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// runWorker запускает задачу раз в interval до отмены ctx
+func (a *Application) runWorker(ctx context.Context, wk worker) {
+	ctx = a.logger.With().Str("worker", wk.name).Logger().WithContext(ctx)
+	ticker := time.NewTicker(wk.interval)
+	defer ticker.Stop()
+
+	// До первого запуска воркер считается живым: первый тик наступит только через interval
+	wk.heartbeat.Beat()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := wk.run(ctx)
+			switch {
+			case err == nil:
+				wk.heartbeat.Beat()
+			case ctx.Err() == nil:
+				zerolog.Ctx(ctx).Error().Err(err).Msg("background job failed")
+				wk.heartbeat.Fail(err)
+			}
+		}
+	}
+}
+
+// newAuthenticator собирает цепочку из статических токенов и JWT.
+// Если ничего не настроено, аутентификация выключена.
+func newAuthenticator(cfg *config.Config) (auth.Authenticator, error) {
+	var chain auth.Chain
+
+	if len(cfg.Auth.AdminTokens) > 0 || len(cfg.Auth.UserTokens) > 0 {
+		chain = append(chain, auth.NewTokenAuthenticator(cfg.Auth.AdminTokens, cfg.Auth.UserTokens))
+	}
+
+	jwtCfg := cfg.Auth.JWT
+	var keys auth.KeySet
+	switch {
+	case jwtCfg.JWKSFile != "":
+		fileKeys, err := auth.NewFileKeySet(jwtCfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		keys = fileKeys
+	case jwtCfg.JWKSURL != "":
+		keys = auth.NewRemoteKeySet(jwtCfg.JWKSURL, nil, jwtCfg.JWKSRefreshInterval)
+	}
+	if keys != nil {
+		chain = append(chain, auth.NewJWTAuthenticator(keys, auth.JWTConfig{
+			Issuer:     jwtCfg.Issuer,
+			Audience:   jwtCfg.Audience,
+			UserClaim:  jwtCfg.UserClaim,
+			RolesClaim: jwtCfg.RolesClaim,
+			AdminRole:  jwtCfg.AdminRole,
+			Leeway:     jwtCfg.Leeway,
+		}))
+	}
+
+	if len(chain) == 0 {
+		return auth.Disabled{}, nil
+	}
+	return chain, nil
 }
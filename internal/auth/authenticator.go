@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+var (
+	ErrMissingCredentials = errors.New("missing bearer token")
+	ErrInvalidCredentials = errors.New("invalid bearer token")
+)
+
+type Authenticator interface {
+	Authenticate(r *http.Request) (Identity, error)
+}
+
+// BearerToken извлекает токен из заголовка Authorization: Bearer <token>
+func BearerToken(r *http.Request) (string, error) {
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return "", ErrMissingCredentials
+	}
+
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", ErrInvalidCredentials
+	}
+	return strings.TrimSpace(token), nil
+}
+
+// TokenAuthenticator проверяет статические токены из конфигурации
+type TokenAuthenticator struct {
+	tokens map[string]Identity
+}
+
+// NewTokenAuthenticator принимает список админских токенов и соответствие "токен -> user_id" для пользователей
+func NewTokenAuthenticator(adminTokens []string, userTokens map[string]string) *TokenAuthenticator {
+	tokens := make(map[string]Identity, len(adminTokens)+len(userTokens))
+	for token, userID := range userTokens {
+		tokens[token] = Identity{UserID: userID, Scope: ScopeUser}
+	}
+	for _, token := range adminTokens {
+		tokens[token] = Identity{Scope: ScopeAdmin}
+	}
+
+	return &TokenAuthenticator{
+		tokens: tokens,
+	}
+}
+
+func (a *TokenAuthenticator) Authenticate(r *http.Request) (Identity, error) {
+	token, err := BearerToken(r)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	identity, ok := a.tokens[token]
+	if !ok {
+		return Identity{}, ErrInvalidCredentials
+	}
+	return identity, nil
+}
+
+// Disabled пропускает все запросы с правами администратора. Используется, когда токены не настроены.
+type Disabled struct{}
+
+func (Disabled) Authenticate(r *http.Request) (Identity, error) {
+	return Identity{Scope: ScopeAdmin}, nil
+}
@@ -0,0 +1,49 @@
+package auth
+
+import "context"
+
+type Scope string
+
+const (
+	ScopeAdmin Scope = "admin"
+	ScopeUser  Scope = "user"
+)
+
+// Identity описывает вызывающего. Для ScopeUser UserID — пользователь, от имени которого выполняется запрос.
+type Identity struct {
+	UserID string
+	Scope  Scope
+}
+
+func (i Identity) IsAdmin() bool {
+	return i.Scope == ScopeAdmin
+}
+
+// CanActAs сообщает, может ли вызывающий выполнять действия от имени пользователя userID
+func (i Identity) CanActAs(userID string) bool {
+	return i.IsAdmin() || (i.UserID != "" && i.UserID == userID)
+}
+
+type identityKey struct{}
+
+func WithIdentity(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+func FromContext(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(Identity)
+	return identity, ok
+}
+
+type actorKey struct{}
+
+// WithActor сохраняет в контексте ID пользователя, от имени которого выполняется изменение
+func WithActor(ctx context.Context, actorID string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actorID)
+}
+
+// ActorFromContext возвращает ID действующего пользователя или пустую строку, если он не известен
+func ActorFromContext(ctx context.Context) string {
+	actorID, _ := ctx.Value(actorKey{}).(string)
+	return actorID
+}
@@ -0,0 +1,249 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// minJWKSRefetchInterval ограничивает частоту попыток загрузки JWKS, в том числе неудачных
+const minJWKSRefetchInterval = 10 * time.Second
+
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// KeySet возвращает публичный ключ по kid из заголовка JWT
+type KeySet interface {
+	Key(ctx context.Context, kid string) (crypto.PublicKey, error)
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jwkSet struct {
+	Keys []jwk `json:"keys"`
+}
+
+// ParseJWKS разбирает JWKS-документ. Поддерживаются ключи RSA и EC P-256; ключи с use != sig пропускаются.
+func ParseJWKS(data []byte) (map[string]crypto.PublicKey, error) {
+	var set jwkSet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("parse jwks: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("parse jwk %q: %w", k.Kid, err)
+		}
+		if key != nil {
+			keys[k.Kid] = key
+		}
+	}
+
+	return keys, nil
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() {
+			return nil, errors.New("rsa exponent is too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !elliptic.P256().IsOnCurve(x, y) {
+			return nil, errors.New("point is not on curve")
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+	default:
+		// Ключи других типов просто не используются
+		return nil, nil
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(raw), nil
+}
+
+// StaticKeySet — неизменяемый набор ключей (JWKS из файла или тестовые ключи)
+type StaticKeySet struct {
+	keys map[string]crypto.PublicKey
+}
+
+func NewStaticKeySet(keys map[string]crypto.PublicKey) *StaticKeySet {
+	return &StaticKeySet{keys: keys}
+}
+
+func NewFileKeySet(path string) (*StaticKeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read jwks file: %w", err)
+	}
+
+	keys, err := ParseJWKS(data)
+	if err != nil {
+		return nil, err
+	}
+	return NewStaticKeySet(keys), nil
+}
+
+func (s *StaticKeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	key, ok := s.keys[kid]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	return key, nil
+}
+
+// RemoteKeySet загружает JWKS по URL и обновляет его раз в refreshInterval,
+// а также при появлении неизвестного kid. Попытки загрузки, в том числе неудачные,
+// выполняются не чаще minJWKSRefetchInterval, а параллельные запросы ждут одну загрузку.
+type RemoteKeySet struct {
+	url             string
+	client          *http.Client
+	refreshInterval time.Duration
+
+	mu          sync.Mutex
+	keys        map[string]crypto.PublicKey
+	fetchedAt   time.Time
+	attemptedAt time.Time
+	lastErr     error
+	// inflight закрывается по окончании текущей загрузки; nil, если загрузки нет
+	inflight chan struct{}
+}
+
+func NewRemoteKeySet(url string, client *http.Client, refreshInterval time.Duration) *RemoteKeySet {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &RemoteKeySet{
+		url:             url,
+		client:          client,
+		refreshInterval: refreshInterval,
+	}
+}
+
+func (s *RemoteKeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	s.mu.Lock()
+	stale := s.keys == nil || (s.refreshInterval > 0 && time.Since(s.fetchedAt) > s.refreshInterval)
+	key, ok := s.keys[kid]
+	if ok && !stale {
+		s.mu.Unlock()
+		return key, nil
+	}
+
+	done := s.inflight
+	if done == nil && time.Since(s.attemptedAt) > minJWKSRefetchInterval {
+		// Загрузка идёт без блокировки и без отмены вызывающего: её результат нужен всем
+		done = make(chan struct{})
+		s.inflight = done
+		s.attemptedAt = time.Now()
+		go s.refresh(context.WithoutCancel(ctx), done)
+	}
+	s.mu.Unlock()
+
+	if done != nil {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if key, ok := s.keys[kid]; ok {
+		return key, nil
+	}
+	if s.keys == nil && s.lastErr != nil {
+		return nil, s.lastErr
+	}
+	return nil, ErrUnknownKey
+}
+
+// refresh загружает JWKS и публикует результат; при ошибке остаются прежние ключи
+func (s *RemoteKeySet) refresh(ctx context.Context, done chan struct{}) {
+	keys, err := s.fetch(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err == nil {
+		s.keys = keys
+		s.fetchedAt = time.Now()
+	}
+	s.lastErr = err
+	s.inflight = nil
+	close(done)
+}
+
+func (s *RemoteKeySet) fetch(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build jwks request: %w", err)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch jwks: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch jwks: unexpected status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("read jwks: %w", err)
+	}
+
+	return ParseJWKS(data)
+}
@@ -0,0 +1,221 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	AlgRS256 = "RS256"
+	AlgES256 = "ES256"
+)
+
+type JWTConfig struct {
+	// Issuer и Audience проверяются, только если заданы
+	Issuer   string
+	Audience string
+	// UserClaim — claim, значение которого становится domain.User.ID
+	UserClaim string
+	// Если у токена в RolesClaim есть AdminRole, вызывающий получает права администратора
+	RolesClaim string
+	AdminRole  string
+	// Leeway — допустимое расхождение часов при проверке exp и nbf
+	Leeway time.Duration
+}
+
+// JWTAuthenticator проверяет JWT, подписанные RS256 или ES256 ключами из KeySet
+type JWTAuthenticator struct {
+	keys KeySet
+	cfg  JWTConfig
+	now  func() time.Time
+}
+
+func NewJWTAuthenticator(keys KeySet, cfg JWTConfig) *JWTAuthenticator {
+	if cfg.UserClaim == "" {
+		cfg.UserClaim = "sub"
+	}
+	return &JWTAuthenticator{
+		keys: keys,
+		cfg:  cfg,
+		now:  time.Now,
+	}
+}
+
+func (a *JWTAuthenticator) Authenticate(r *http.Request) (Identity, error) {
+	token, err := BearerToken(r)
+	if err != nil {
+		return Identity{}, err
+	}
+	return a.Verify(r.Context(), token)
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// Verify проверяет подпись и стандартные claims токена и возвращает Identity вызывающего
+func (a *JWTAuthenticator) Verify(ctx context.Context, token string) (Identity, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Identity{}, invalidToken("malformed token")
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return Identity{}, invalidToken("malformed header")
+	}
+
+	key, err := a.keys.Key(ctx, header.Kid)
+	if err != nil {
+		return Identity{}, invalidToken(err.Error())
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Identity{}, invalidToken("malformed signature")
+	}
+	if err := verifySignature(header.Alg, key, parts[0]+"."+parts[1], signature); err != nil {
+		return Identity{}, err
+	}
+
+	var claims map[string]any
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return Identity{}, invalidToken("malformed claims")
+	}
+	if err := a.validateClaims(claims); err != nil {
+		return Identity{}, err
+	}
+
+	userID, _ := claims[a.cfg.UserClaim].(string)
+	if userID == "" {
+		return Identity{}, invalidToken(fmt.Sprintf("claim %q is missing", a.cfg.UserClaim))
+	}
+
+	identity := Identity{UserID: userID, Scope: ScopeUser}
+	if a.cfg.AdminRole != "" && slices.Contains(stringList(claims[a.cfg.RolesClaim]), a.cfg.AdminRole) {
+		identity.Scope = ScopeAdmin
+	}
+	return identity, nil
+}
+
+func (a *JWTAuthenticator) validateClaims(claims map[string]any) error {
+	now := a.now()
+
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return invalidToken("exp claim is required")
+	}
+	if now.After(time.Unix(int64(exp), 0).Add(a.cfg.Leeway)) {
+		return invalidToken("token expired")
+	}
+
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(a.cfg.Leeway).Before(time.Unix(int64(nbf), 0)) {
+		return invalidToken("token is not valid yet")
+	}
+
+	if a.cfg.Issuer != "" {
+		if iss, _ := claims["iss"].(string); iss != a.cfg.Issuer {
+			return invalidToken("unexpected issuer")
+		}
+	}
+
+	if a.cfg.Audience != "" && !slices.Contains(stringList(claims["aud"]), a.cfg.Audience) {
+		return invalidToken("unexpected audience")
+	}
+
+	return nil
+}
+
+func verifySignature(alg string, key crypto.PublicKey, signingInput string, signature []byte) error {
+	digest := sha256.Sum256([]byte(signingInput))
+
+	switch alg {
+	case AlgRS256:
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return invalidToken("key type does not match alg")
+		}
+		if err := rsa.VerifyPKCS1v15(rsaKey, crypto.SHA256, digest[:], signature); err != nil {
+			return invalidToken("invalid signature")
+		}
+	case AlgES256:
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return invalidToken("key type does not match alg")
+		}
+		// Подпись JWS ES256 — конкатенация r и s по 32 байта
+		if len(signature) != 64 {
+			return invalidToken("invalid signature")
+		}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(ecKey, digest[:], r, s) {
+			return invalidToken("invalid signature")
+		}
+	default:
+		return invalidToken(fmt.Sprintf("unsupported alg %q", alg))
+	}
+
+	return nil
+}
+
+func decodeSegment(segment string, v any) error {
+	raw, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// stringList приводит claim, который может быть строкой или массивом строк, к срезу
+func stringList(v any) []string {
+	switch value := v.(type) {
+	case string:
+		return []string{value}
+	case []any:
+		list := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	default:
+		return nil
+	}
+}
+
+func invalidToken(reason string) error {
+	return fmt.Errorf("%w: %s", ErrInvalidCredentials, reason)
+}
+
+// Chain пробует аутентификаторы по очереди и возвращает первый успешный результат
+type Chain []Authenticator
+
+func (c Chain) Authenticate(r *http.Request) (Identity, error) {
+	err := ErrMissingCredentials
+	for _, authenticator := range c {
+		identity, authErr := authenticator.Authenticate(r)
+		if authErr == nil {
+			return identity, nil
+		}
+		if errors.Is(authErr, ErrMissingCredentials) {
+			return Identity{}, authErr
+		}
+		err = authErr
+	}
+	return Identity{}, err
+}
@@ -0,0 +1,295 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func signToken(t *testing.T, alg, kid string, key crypto.Signer, claims map[string]any) string {
+	t.Helper()
+
+	header, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+
+	var signature []byte
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		sig, err := rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatalf("sign rs256: %v", err)
+		}
+		signature = sig
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest[:])
+		if err != nil {
+			t.Fatalf("sign es256: %v", err)
+		}
+		signature = make([]byte, 64)
+		r.FillBytes(signature[:32])
+		s.FillBytes(signature[32:])
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func encodeInt(n *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(n.Bytes())
+}
+
+func jwksDocument(rsaKey *rsa.PublicKey, ecKey *ecdsa.PublicKey) []byte {
+	doc, _ := json.Marshal(map[string]any{
+		"keys": []map[string]string{
+			{"kty": "RSA", "kid": "rsa-1", "use": "sig", "n": encodeInt(rsaKey.N), "e": encodeInt(big.NewInt(int64(rsaKey.E)))},
+			{"kty": "EC", "kid": "ec-1", "crv": "P-256", "x": encodeInt(ecKey.X), "y": encodeInt(ecKey.Y)},
+		},
+	})
+	return doc
+}
+
+func TestJWTAuthenticator(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate ec key: %v", err)
+	}
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate ec key: %v", err)
+	}
+
+	keys, err := ParseJWKS(jwksDocument(&rsaKey.PublicKey, &ecKey.PublicKey))
+	if err != nil {
+		t.Fatalf("parse jwks: %v", err)
+	}
+
+	authenticator := NewJWTAuthenticator(NewStaticKeySet(keys), JWTConfig{
+		Issuer:     "https://idp.example",
+		Audience:   "pr-reviewer",
+		UserClaim:  "employee_id",
+		RolesClaim: "roles",
+		AdminRole:  "reviewer-admin",
+	})
+
+	validClaims := func() map[string]any {
+		return map[string]any{
+			"iss":         "https://idp.example",
+			"aud":         []string{"pr-reviewer", "other"},
+			"exp":         time.Now().Add(time.Hour).Unix(),
+			"employee_id": "u1",
+		}
+	}
+
+	tests := []struct {
+		name      string
+		token     func() string
+		wantUser  string
+		wantScope Scope
+		wantErr   bool
+	}{
+		{
+			name:      "RS256",
+			token:     func() string { return signToken(t, AlgRS256, "rsa-1", rsaKey, validClaims()) },
+			wantUser:  "u1",
+			wantScope: ScopeUser,
+		},
+		{
+			name:      "ES256",
+			token:     func() string { return signToken(t, AlgES256, "ec-1", ecKey, validClaims()) },
+			wantUser:  "u1",
+			wantScope: ScopeUser,
+		},
+		{
+			name: "admin role",
+			token: func() string {
+				claims := validClaims()
+				claims["roles"] = []string{"reviewer-admin"}
+				return signToken(t, AlgES256, "ec-1", ecKey, claims)
+			},
+			wantUser:  "u1",
+			wantScope: ScopeAdmin,
+		},
+		{
+			name:    "foreign key",
+			token:   func() string { return signToken(t, AlgES256, "ec-1", otherKey, validClaims()) },
+			wantErr: true,
+		},
+		{
+			name:    "unknown kid",
+			token:   func() string { return signToken(t, AlgES256, "ec-2", ecKey, validClaims()) },
+			wantErr: true,
+		},
+		{
+			name:    "alg does not match key",
+			token:   func() string { return signToken(t, AlgES256, "rsa-1", ecKey, validClaims()) },
+			wantErr: true,
+		},
+		{
+			name: "expired",
+			token: func() string {
+				claims := validClaims()
+				claims["exp"] = time.Now().Add(-time.Hour).Unix()
+				return signToken(t, AlgRS256, "rsa-1", rsaKey, claims)
+			},
+			wantErr: true,
+		},
+		{
+			name: "wrong audience",
+			token: func() string {
+				claims := validClaims()
+				claims["aud"] = "other"
+				return signToken(t, AlgRS256, "rsa-1", rsaKey, claims)
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing user claim",
+			token: func() string {
+				claims := validClaims()
+				delete(claims, "employee_id")
+				return signToken(t, AlgRS256, "rsa-1", rsaKey, claims)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token())
+
+			identity, err := authenticator.Authenticate(req)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidCredentials) {
+					t.Fatalf("expected ErrInvalidCredentials, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if identity.UserID != tt.wantUser || identity.Scope != tt.wantScope {
+				t.Fatalf("got %+v, want user %q scope %q", identity, tt.wantUser, tt.wantScope)
+			}
+		})
+	}
+}
+
+func TestRemoteKeySet(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate ec key: %v", err)
+	}
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(jwksDocument(&rsaKey.PublicKey, &ecKey.PublicKey))
+	}))
+	defer server.Close()
+
+	authenticator := NewJWTAuthenticator(NewRemoteKeySet(server.URL, server.Client(), time.Minute), JWTConfig{})
+	token := signToken(t, AlgES256, "ec-1", ecKey, map[string]any{
+		"sub": "u2",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+
+	identity, err := authenticator.Verify(context.Background(), token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if identity.UserID != "u2" {
+		t.Fatalf("got user %q, want u2", identity.UserID)
+	}
+}
+
+func TestRemoteKeySetThrottlesFailedFetches(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	keys := NewRemoteKeySet(server.URL, server.Client(), time.Minute)
+	for i := 0; i < 3; i++ {
+		if _, err := keys.Key(context.Background(), "ec-1"); err == nil || errors.Is(err, ErrUnknownKey) {
+			t.Fatalf("expected fetch error, got %v", err)
+		}
+	}
+	if n := requests.Load(); n != 1 {
+		t.Fatalf("failed JWKS fetched %d times, want 1", n)
+	}
+}
+
+func TestRemoteKeySetSharesConcurrentFetch(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate ec key: %v", err)
+	}
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+
+	var requests atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+		w.Write(jwksDocument(&rsaKey.PublicKey, &ecKey.PublicKey))
+	}))
+	defer server.Close()
+
+	keys := NewRemoteKeySet(server.URL, server.Client(), time.Minute)
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := keys.Key(context.Background(), "ec-1")
+			errs <- err
+		}()
+	}
+
+	// Пока идёт загрузка, отменённый запрос не ждёт её окончания
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := keys.Key(ctx, "ec-1"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	close(release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if n := requests.Load(); n != 1 {
+		t.Fatalf("JWKS fetched %d times, want 1", n)
+	}
+}
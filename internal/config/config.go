@@ -18,6 +18,50 @@ type Config struct {
 		Addr            string        `env:"HTTP_ADDR" envDefault:":8080"`
 		ReadHeader      time.Duration `env:"HTTP_READ_HEADER_TIMEOUT" envDefault:"5s"`
 		ShutdownTimeout time.Duration `env:"HTTP_SHUTDOWN_TIMEOUT" envDefault:"5s"`
+		// Сколько отдавать not-ready на /readyz перед остановкой сервера
+		DrainDelay time.Duration `env:"HTTP_DRAIN_DELAY" envDefault:"0s"`
+	}
+	Health struct {
+		CheckTimeout time.Duration `env:"HEALTH_CHECK_TIMEOUT" envDefault:"2s"`
+	}
+	Log struct {
+		Level  string `env:"LOG_LEVEL" envDefault:"info"`
+		Format string `env:"LOG_FORMAT" envDefault:"json"`
+	}
+	// Если не заданы ни токены, ни JWKS, аутентификация выключена
+	Auth struct {
+		AdminTokens []string          `env:"AUTH_ADMIN_TOKENS" envSeparator:","`
+		UserTokens  map[string]string `env:"AUTH_USER_TOKENS" envSeparator:"," envKeyValSeparator:":"`
+		JWT         struct {
+			JWKSFile            string        `env:"AUTH_JWKS_FILE"`
+			JWKSURL             string        `env:"AUTH_JWKS_URL"`
+			JWKSRefreshInterval time.Duration `env:"AUTH_JWKS_REFRESH_INTERVAL" envDefault:"5m"`
+			Issuer              string        `env:"AUTH_JWT_ISSUER"`
+			Audience            string        `env:"AUTH_JWT_AUDIENCE"`
+			UserClaim           string        `env:"AUTH_JWT_USER_CLAIM" envDefault:"sub"`
+			RolesClaim          string        `env:"AUTH_JWT_ROLES_CLAIM" envDefault:"roles"`
+			AdminRole           string        `env:"AUTH_JWT_ADMIN_ROLE"`
+			Leeway              time.Duration `env:"AUTH_JWT_LEEWAY" envDefault:"30s"`
+		}
+	}
+	// Раз в ReassignInterval фоновая задача снимает открытые ревью с пользователей, у которых начался
+	// интервал недоступности с reassign_reviews
+	Availability struct {
+		ReassignInterval time.Duration `env:"AVAILABILITY_REASSIGN_INTERVAL" envDefault:"1m"`
+	}
+	// Ответы на POST запросы с Idempotency-Key хранятся TTL; истёкшие удаляются раз в CleanupInterval
+	Idempotency struct {
+		TTL             time.Duration `env:"IDEMPOTENCY_TTL" envDefault:"24h"`
+		CleanupInterval time.Duration `env:"IDEMPOTENCY_CLEANUP_INTERVAL" envDefault:"1m"`
+	}
+	// Exporter: none, stdout, file или otlp
+	Tracing struct {
+		Exporter     string  `env:"TRACING_EXPORTER" envDefault:"none"`
+		File         string  `env:"TRACING_FILE" envDefault:"traces.jsonl"`
+		OTLPEndpoint string  `env:"TRACING_OTLP_ENDPOINT"`
+		OTLPInsecure bool    `env:"TRACING_OTLP_INSECURE"`
+		SampleRatio  float64 `env:"TRACING_SAMPLE_RATIO" envDefault:"1"`
+		ServiceName  string  `env:"TRACING_SERVICE_NAME" envDefault:"pr-reviewer-service"`
 	}
 }
 
@@ -36,9 +80,15 @@ func Load() (*Config, error) {
 	if cfg.HTTP.ShutdownTimeout == 0 {
 		cfg.HTTP.ShutdownTimeout = defaultShutdownTimeout
 	}
+	if cfg.Availability.ReassignInterval <= 0 {
+		return nil, fmt.Errorf("AVAILABILITY_REASSIGN_INTERVAL must be positive")
+	}
+	if cfg.Idempotency.TTL <= 0 {
+		return nil, fmt.Errorf("IDEMPOTENCY_TTL must be positive")
+	}
+	if cfg.Idempotency.CleanupInterval <= 0 {
+		return nil, fmt.Errorf("IDEMPOTENCY_CLEANUP_INTERVAL must be positive")
+	}
 
 	return cfg, nil
 }
-
-
-
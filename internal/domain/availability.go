@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+type UnavailabilityReason string
+
+const (
+	UnavailabilityReasonVacation UnavailabilityReason = "vacation"
+	UnavailabilityReasonOnCall   UnavailabilityReason = "on_call"
+	UnavailabilityReasonPartTime UnavailabilityReason = "part_time"
+	UnavailabilityReasonOther    UnavailabilityReason = "other"
+)
+
+func (r UnavailabilityReason) IsValid() bool {
+	switch r {
+	case UnavailabilityReasonVacation, UnavailabilityReasonOnCall, UnavailabilityReasonPartTime, UnavailabilityReasonOther:
+		return true
+	default:
+		return false
+	}
+}
+
+// Unavailability — интервал [StartsAt, EndsAt), в который пользователь не назначается ревьюером
+type Unavailability struct {
+	ID       string
+	UserID   string
+	Reason   UnavailabilityReason
+	StartsAt time.Time
+	EndsAt   time.Time
+	// ReassignReviews — снять с пользователя открытые ревью, когда интервал начнётся
+	ReassignReviews bool
+	// ReviewsReassignedAt — когда фоновая задача сняла открытые ревью (nil, если ещё не снимала)
+	ReviewsReassignedAt *time.Time
+	CreatedAt           time.Time
+}
+
+// Covers сообщает, попадает ли момент t в интервал
+func (u Unavailability) Covers(t time.Time) bool {
+	return !t.Before(u.StartsAt) && t.Before(u.EndsAt)
+}
@@ -1,5 +1,7 @@
 package domain
 
+// ErrorCode — код ошибки, который видит клиент. Реализует error,
+// чтобы проверять код через errors.Is(err, ErrorCodeNotFound).
 type ErrorCode string
 
 const (
@@ -9,7 +11,64 @@ const (
 	ErrorCodeNotAssigned ErrorCode = "NOT_ASSIGNED"
 	ErrorCodeNoCandidate ErrorCode = "NO_CANDIDATE"
 	ErrorCodeNotFound    ErrorCode = "NOT_FOUND"
+
+	ErrorCodeTeamHasOpenReviews ErrorCode = "TEAM_HAS_OPEN_REVIEWS"
+	ErrorCodeUnauthorized       ErrorCode = "UNAUTHORIZED"
+	ErrorCodeForbidden          ErrorCode = "FORBIDDEN"
+	ErrorCodeValidation         ErrorCode = "VALIDATION_ERROR"
+	ErrorCodeInternal           ErrorCode = "INTERNAL"
+
+	ErrorCodePreconditionFailed ErrorCode = "PRECONDITION_FAILED"
+	ErrorCodeConcurrentUpdate   ErrorCode = "CONCURRENT_UPDATE"
+
+	ErrorCodeIdempotencyKeyReused ErrorCode = "IDEMPOTENCY_KEY_REUSED"
+	ErrorCodeIdempotencyKeyInUse  ErrorCode = "IDEMPOTENCY_KEY_IN_USE"
 )
 
+func (c ErrorCode) Error() string {
+	return string(c)
+}
+
+// Kind возвращает класс ошибки для кода; неизвестные коды считаются внутренними
+func (c ErrorCode) Kind() ErrorKind {
+	switch c {
+	case ErrorCodeValidation:
+		return KindInvalidInput
+	case ErrorCodeNotFound:
+		return KindNotFound
+	case ErrorCodeTeamExists, ErrorCodePRExists, ErrorCodePRMerged, ErrorCodeNotAssigned,
+		ErrorCodeNoCandidate, ErrorCodeTeamHasOpenReviews, ErrorCodeConcurrentUpdate, ErrorCodeIdempotencyKeyInUse:
+		return KindConflict
+	case ErrorCodeIdempotencyKeyReused:
+		return KindUnprocessable
+	case ErrorCodePreconditionFailed:
+		return KindPreconditionFailed
+	case ErrorCodeUnauthorized:
+		return KindUnauthorized
+	case ErrorCodeForbidden:
+		return KindForbidden
+	default:
+		return KindInternal
+	}
+}
 
+// ErrorKind — класс ошибки, по которому транспорт выбирает статус ответа.
+// Реализует error, чтобы проверять класс через errors.Is(err, KindConflict).
+type ErrorKind string
+
+const (
+	KindInvalidInput ErrorKind = "invalid_input"
+	KindNotFound     ErrorKind = "not_found"
+	KindConflict     ErrorKind = "conflict"
+	KindUnauthorized ErrorKind = "unauthorized"
+	KindForbidden    ErrorKind = "forbidden"
+	KindInternal     ErrorKind = "internal"
+	// KindPreconditionFailed — не выполнено условие запроса (If-Match): клиент видел устаревшую версию
+	KindPreconditionFailed ErrorKind = "precondition_failed"
+	// KindUnprocessable — запрос корректен, но противоречит ранее принятому (ключ идемпотентности с другим телом)
+	KindUnprocessable ErrorKind = "unprocessable"
+)
 
+func (k ErrorKind) Error() string {
+	return string(k)
+}
@@ -1,10 +1,19 @@
 package domain
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"maps"
+)
 
+// DomainError — ошибка бизнес-логики с кодом для клиента.
+// Details содержит структурированные данные об ошибке (идентификаторы ресурсов и т.п.),
+// Err — исходную причину, доступную через errors.Unwrap.
 type DomainError struct {
 	Code    ErrorCode
 	Message string
+	Details map[string]any
+	Err     error
 }
 
 func (e DomainError) Error() string {
@@ -14,6 +23,34 @@ func (e DomainError) Error() string {
 	return string(e.Code)
 }
 
+func (e DomainError) Unwrap() error {
+	return e.Err
+}
+
+// Is позволяет сравнивать ошибку с кодом и с классом: errors.Is(err, ErrorCodeNotFound), errors.Is(err, KindConflict)
+func (e DomainError) Is(target error) bool {
+	switch t := target.(type) {
+	case ErrorCode:
+		return e.Code == t
+	case ErrorKind:
+		return e.Code.Kind() == t
+	}
+	return false
+}
+
+func (e DomainError) Kind() ErrorKind {
+	return e.Code.Kind()
+}
+
+// WithDetail возвращает копию ошибки с добавленным полем в Details
+func (e DomainError) WithDetail(key string, value any) DomainError {
+	details := make(map[string]any, len(e.Details)+1)
+	maps.Copy(details, e.Details)
+	details[key] = value
+	e.Details = details
+	return e
+}
+
 func NewDomainError(code ErrorCode, format string, args ...any) error {
 	return DomainError{
 		Code:    code,
@@ -21,5 +58,24 @@ func NewDomainError(code ErrorCode, format string, args ...any) error {
 	}
 }
 
+// WrapDomainError создаёт доменную ошибку, сохраняя err как причину
+func WrapDomainError(err error, code ErrorCode, format string, args ...any) error {
+	return DomainError{
+		Code:    code,
+		Message: fmt.Sprintf(format, args...),
+		Err:     err,
+	}
+}
 
-
+// KindOf возвращает класс ошибки; всё, что не является доменной ошибкой, считается внутренней
+func KindOf(err error) ErrorKind {
+	var validationErr ValidationError
+	if errors.As(err, &validationErr) {
+		return KindInvalidInput
+	}
+	var domainErr DomainError
+	if errors.As(err, &domainErr) {
+		return domainErr.Kind()
+	}
+	return KindInternal
+}
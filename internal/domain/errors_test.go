@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestDomainErrorMatching(t *testing.T) {
+	cause := errors.New("connection reset")
+	err := fmt.Errorf("create pr: %w",
+		DomainError{Code: ErrorCodePRExists, Message: "PR id already exists", Err: cause}.
+			WithDetail("pull_request_id", "pr-1"))
+
+	if !errors.Is(err, ErrorCodePRExists) {
+		t.Error("wrapped error does not match its code")
+	}
+	if !errors.Is(err, KindConflict) {
+		t.Error("error does not match its kind")
+	}
+	if errors.Is(err, ErrorCodeNotFound) || errors.Is(err, KindNotFound) {
+		t.Error("error matches a foreign code or kind")
+	}
+	if !errors.Is(err, cause) {
+		t.Error("cause is not reachable via errors.Is")
+	}
+
+	var domainErr DomainError
+	if !errors.As(err, &domainErr) || domainErr.Details["pull_request_id"] != "pr-1" {
+		t.Errorf("errors.As returned %+v", domainErr)
+	}
+}
+
+func TestKindOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorKind
+	}{
+		{"validation", NewValidationError(FieldViolation{Field: "team_name", Message: "is required"}), KindInvalidInput},
+		{"not found", NewDomainError(ErrorCodeNotFound, "team not found"), KindNotFound},
+		{"forbidden", fmt.Errorf("wrapped: %w", NewDomainError(ErrorCodeForbidden, "denied")), KindForbidden},
+		{"unknown code", NewDomainError("SOMETHING", "boom"), KindInternal},
+		{"plain error", errors.New("boom"), KindInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := KindOf(tt.err); got != tt.want {
+				t.Errorf("KindOf() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// IdempotencyRecord — сохранённый результат запроса с заголовком Idempotency-Key.
+// Запись идентифицируется парой (Key, Route); RequestHash отличает повтор того же запроса
+// от другого запроса под тем же ключом.
+type IdempotencyRecord struct {
+	Key         string
+	Route       string
+	RequestHash string
+	// Response равен nil, пока первый запрос с этим ключом ещё выполняется
+	Response  *IdempotentResponse
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// IdempotentResponse — ответ, который повторяется для дубликатов запроса
+type IdempotentResponse struct {
+	StatusCode int
+	// Header содержит только заголовки, значимые для клиента (Content-Type, ETag, Location)
+	Header map[string]string
+	Body   []byte
+}
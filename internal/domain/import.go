@@ -0,0 +1,50 @@
+package domain
+
+// ImportRecord — одна запись выгрузки. Заполнено ровно одно из полей с данными.
+type ImportRecord struct {
+	// Line — номер строки во входном файле (для отчёта)
+	Line           int
+	Team           *Team
+	User           *User
+	Role           *RoleAssignment
+	Unavailability *Unavailability
+	Rotation       *ReviewerRotation
+	PullRequest    *PullRequest
+}
+
+// ImportConflict — запись, пропущенная из-за расхождения с уже сохранёнными данными
+type ImportConflict struct {
+	Line   int
+	Kind   string
+	ID     string
+	Reason string
+}
+
+// ImportViolation — ошибка во входных данных, из-за которой импорт не применяется
+type ImportViolation struct {
+	Line    int
+	Message string
+}
+
+type ImportReport struct {
+	DryRun                 bool
+	Applied                bool
+	TeamsImported          int
+	UsersImported          int
+	PullRequestsImported   int
+	RolesImported          int
+	UnavailabilityImported int
+	RotationsImported      int
+	Conflicts              []ImportConflict
+	Violations             []ImportViolation
+}
+
+const (
+	ImportKindTeam        = "team"
+	ImportKindUser        = "user"
+	ImportKindPullRequest = "pull_request"
+
+	ImportKindRole           = "role"
+	ImportKindUnavailability = "unavailability"
+	ImportKindRotation       = "reviewer_rotation"
+)
@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
 type TeamMember struct {
 	UserID   string
@@ -18,8 +21,39 @@ type User struct {
 	Username string
 	TeamName string
 	IsActive bool
+	// MaxOpenReviews — сколько открытых ревью можно назначить пользователю одновременно; 0 — без ограничения
+	MaxOpenReviews int
+	// Skills — теги экспертизы пользователя (go, sql, frontend...), нормализованные NormalizeSkills
+	Skills []string
 }
 
+// Equal сравнивает пользователей по всем полям
+func (u User) Equal(other User) bool {
+	return u.ID == other.ID &&
+		u.Username == other.Username &&
+		u.TeamName == other.TeamName &&
+		u.IsActive == other.IsActive &&
+		u.MaxOpenReviews == other.MaxOpenReviews &&
+		slices.Equal(u.Skills, other.Skills)
+}
+
+// HasCapacity сообщает, можно ли назначить пользователю ещё одно ревью, если у него openReviews открытых
+func (u User) HasCapacity(openReviews int) bool {
+	return u.MaxOpenReviews <= 0 || openReviews < u.MaxOpenReviews
+}
+
+// ReviewerShortage объясняет, почему PR не хватает ревьюеров
+type ReviewerShortage string
+
+const (
+	// ReviewerShortageNoCandidates — в команде нет других активных участников
+	ReviewerShortageNoCandidates ReviewerShortage = "no_candidates"
+	// ReviewerShortageUnavailable — подходящие участники в отпуске или иначе недоступны
+	ReviewerShortageUnavailable ReviewerShortage = "unavailable"
+	// ReviewerShortageAtCapacity — у подходящих участников исчерпан лимит открытых ревью
+	ReviewerShortageAtCapacity ReviewerShortage = "at_capacity"
+)
+
 type PullRequest struct {
 	ID                string
 	Name              string
@@ -27,8 +61,37 @@ type PullRequest struct {
 	Status            PullRequestStatus
 	AssignedReviewers []string
 	NeedMoreReviewers bool
-	CreatedAt         time.Time
-	MergedAt          *time.Time
+	// ReviewerShortage — почему ревьюеров не хватает; пусто, если NeedMoreReviewers = false
+	ReviewerShortage ReviewerShortage
+	// RequiredSkills — теги экспертизы, которые нужны для ревью PR
+	RequiredSkills []string
+	// MatchedSkills — для каждого ревьюера теги из RequiredSkills, по которым он выбран;
+	// ревьюеры без совпадений в карту не попадают
+	MatchedSkills map[string][]string
+	CreatedAt     time.Time
+	MergedAt      *time.Time
+	// MergedBy — пользователь, выполнивший merge (пусто, если не известен)
+	MergedBy      string
+	Reassignments []ReviewerReassignment
+	// Version увеличивается хранилищем при каждом изменении PR; новый PR получает версию 1
+	Version int64
+}
+
+// ReviewerReassignment — запись о замене ревьюера
+type ReviewerReassignment struct {
+	OldReviewerID string
+	NewReviewerID string
+	// ActorID — пользователь, инициировавший замену (пусто, если не известен)
+	ActorID string
+	At      time.Time
+}
+
+// PullRequestDetails — PR вместе с данными автора и ревьюеров
+type PullRequestDetails struct {
+	PullRequest PullRequest
+	// Author равен nil, если автор отсутствует в хранилище
+	Author    *User
+	Reviewers []User
 }
 
 func (pr *PullRequest) HasReviewer(userID string) bool {
@@ -44,12 +107,48 @@ func (pr *PullRequest) ReplaceReviewer(oldReviewer, newReviewer string) bool {
 	for idx, reviewerID := range pr.AssignedReviewers {
 		if reviewerID == oldReviewer {
 			pr.AssignedReviewers[idx] = newReviewer
+			delete(pr.MatchedSkills, oldReviewer)
 			return true
 		}
 	}
 	return false
 }
 
+func (pr *PullRequest) RemoveReviewer(userID string) bool {
+	for idx, reviewerID := range pr.AssignedReviewers {
+		if reviewerID == userID {
+			pr.AssignedReviewers = append(pr.AssignedReviewers[:idx], pr.AssignedReviewers[idx+1:]...)
+			delete(pr.MatchedSkills, userID)
+			return true
+		}
+	}
+	return false
+}
+
+// SetMatchedSkills запоминает, по каким тегам выбран ревьюер; пустой список удаляет запись
+func (pr *PullRequest) SetMatchedSkills(reviewerID string, skills []string) {
+	if len(skills) == 0 {
+		delete(pr.MatchedSkills, reviewerID)
+		return
+	}
+	if pr.MatchedSkills == nil {
+		pr.MatchedSkills = make(map[string][]string)
+	}
+	pr.MatchedSkills[reviewerID] = skills
+}
+
+// UpdateNeedMoreReviewers пересчитывает NeedMoreReviewers по числу ревьюеров.
+// shortage запоминается, если ревьюеров не хватает; пустой shortage оставляет прежнюю причину.
+func (pr *PullRequest) UpdateNeedMoreReviewers(shortage ReviewerShortage) {
+	pr.NeedMoreReviewers = len(pr.AssignedReviewers) < 2
+	switch {
+	case !pr.NeedMoreReviewers:
+		pr.ReviewerShortage = ""
+	case shortage != "":
+		pr.ReviewerShortage = shortage
+	}
+}
+
 func (pr *PullRequest) IsMerged() bool {
 	return pr.Status == PullRequestStatusMerged
 }
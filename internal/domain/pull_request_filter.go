@@ -0,0 +1,77 @@
+package domain
+
+import "time"
+
+type PullRequestSortField string
+
+const (
+	PullRequestSortByCreatedAt PullRequestSortField = "created_at"
+	PullRequestSortByMergedAt  PullRequestSortField = "merged_at"
+	PullRequestSortByID        PullRequestSortField = "pull_request_id"
+)
+
+func (f PullRequestSortField) IsValid() bool {
+	switch f {
+	case PullRequestSortByCreatedAt, PullRequestSortByMergedAt, PullRequestSortByID:
+		return true
+	default:
+		return false
+	}
+}
+
+type SortOrder string
+
+const (
+	SortOrderAsc  SortOrder = "asc"
+	SortOrderDesc SortOrder = "desc"
+)
+
+func (o SortOrder) IsValid() bool {
+	return o == SortOrderAsc || o == SortOrderDesc
+}
+
+// PageCursor указывает на последний элемент предыдущей страницы.
+// SortValue — значение поля сортировки (UnixNano для дат, 0 для сортировки по ID и для отсутствующей даты).
+type PageCursor struct {
+	SortBy    PullRequestSortField
+	Order     SortOrder
+	SortValue int64
+	ID        string
+}
+
+// PullRequestFilter описывает выборку PR. Пустые поля не ограничивают результат.
+type PullRequestFilter struct {
+	Status            *PullRequestStatus
+	AuthorIDs         []string
+	ReviewerID        string
+	NeedMoreReviewers *bool
+	CreatedFrom       *time.Time
+	CreatedTo         *time.Time
+	MergedFrom        *time.Time
+	MergedTo          *time.Time
+	SortBy            PullRequestSortField
+	Order             SortOrder
+	After             *PageCursor
+	Limit             int
+}
+
+type PullRequestPage struct {
+	Items []PullRequest
+	// Next равен nil, если страница последняя
+	Next *PageCursor
+}
+
+// SortValue возвращает значение поля сортировки PR для курсора
+func (pr *PullRequest) SortValue(field PullRequestSortField) int64 {
+	switch field {
+	case PullRequestSortByCreatedAt:
+		return pr.CreatedAt.UnixNano()
+	case PullRequestSortByMergedAt:
+		if pr.MergedAt == nil {
+			return 0
+		}
+		return pr.MergedAt.UnixNano()
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,53 @@
+package domain
+
+type Role string
+
+const (
+	RoleAdmin    Role = "admin"
+	RoleTeamLead Role = "team_lead"
+)
+
+func (r Role) IsValid() bool {
+	switch r {
+	case RoleAdmin, RoleTeamLead:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsTeamScoped сообщает, действует ли роль только в пределах одной команды
+func (r Role) IsTeamScoped() bool {
+	return r == RoleTeamLead
+}
+
+type Permission string
+
+const (
+	PermissionManageMembers     Permission = "manage_members"
+	PermissionReassignReviewers Permission = "reassign_reviewers"
+	PermissionEditReviewPolicy  Permission = "edit_review_policy"
+	PermissionManageTeams       Permission = "manage_teams"
+	PermissionManageRoles       Permission = "manage_roles"
+)
+
+// Grants сообщает, даёт ли роль право. Для командных ролей право действует только в их команде.
+func (r Role) Grants(p Permission) bool {
+	switch r {
+	case RoleAdmin:
+		return true
+	case RoleTeamLead:
+		switch p {
+		case PermissionManageMembers, PermissionReassignReviewers, PermissionEditReviewPolicy:
+			return true
+		}
+	}
+	return false
+}
+
+// RoleAssignment — выданная пользователю роль. TeamName заполняется только для командных ролей.
+type RoleAssignment struct {
+	UserID   string
+	Role     Role
+	TeamName string
+}
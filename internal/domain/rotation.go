@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// ReviewerStrategy — способ выбора ревьюеров в команде
+type ReviewerStrategy string
+
+const (
+	// ReviewerStrategyRandom — случайный выбор с ранжированием по тегам экспертизы (по умолчанию)
+	ReviewerStrategyRandom ReviewerStrategy = "random"
+	// ReviewerStrategyRoundRobin — участники назначаются строго по очереди в порядке user_id
+	ReviewerStrategyRoundRobin ReviewerStrategy = "round_robin"
+)
+
+func (s ReviewerStrategy) IsValid() bool {
+	switch s {
+	case ReviewerStrategyRandom, ReviewerStrategyRoundRobin:
+		return true
+	}
+	return false
+}
+
+// ReviewerRotation — стратегия выбора ревьюеров команды и курсор очереди round-robin
+type ReviewerRotation struct {
+	TeamName string
+	Strategy ReviewerStrategy
+	// LastUserID — последний участник, назначенный по очереди; следующим будет участник
+	// со следующим по порядку user_id. Пусто, если по очереди ещё никто не назначался.
+	LastUserID string
+	// Version увеличивается хранилищем при каждом сохранении; 0 — ротация ещё не сохранялась
+	Version   int64
+	UpdatedAt time.Time
+}
+
+// DefaultReviewerRotation — ротация команды, для которой стратегия не задавалась
+func DefaultReviewerRotation(teamName string) ReviewerRotation {
+	return ReviewerRotation{TeamName: teamName, Strategy: ReviewerStrategyRandom}
+}
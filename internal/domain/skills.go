@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+)
+
+// NormalizeSkills приводит теги экспертизы к нижнему регистру, убирает пустые и повторы
+// и сортирует, чтобы одинаковые наборы тегов хранились одинаково
+func NormalizeSkills(skills []string) []string {
+	normalized := make([]string, 0, len(skills))
+	for _, skill := range skills {
+		skill = strings.ToLower(strings.TrimSpace(skill))
+		if skill != "" {
+			normalized = append(normalized, skill)
+		}
+	}
+	slices.Sort(normalized)
+	return slices.Compact(normalized)
+}
+
+// MatchSkills возвращает теги из required, которые есть у пользователя, в порядке required
+func MatchSkills(userSkills, required []string) []string {
+	var matched []string
+	for _, skill := range required {
+		if slices.Contains(userSkills, skill) {
+			matched = append(matched, skill)
+		}
+	}
+	return matched
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNormalizeSkills(t *testing.T) {
+	tests := []struct {
+		name   string
+		skills []string
+		want   []string
+	}{
+		{"nil", nil, []string{}},
+		{"case and spaces", []string{" Go ", "SQL"}, []string{"go", "sql"}},
+		{"duplicates after normalization", []string{"go", "GO", "postgres", "go"}, []string{"go", "postgres"}},
+		{"blank tags are dropped", []string{"", "  ", "k8s"}, []string{"k8s"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeSkills(tt.skills); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchSkills(t *testing.T) {
+	tests := []struct {
+		name     string
+		user     []string
+		required []string
+		want     []string
+	}{
+		{"no required skills", []string{"go"}, nil, nil},
+		{"no user skills", nil, []string{"go"}, nil},
+		{"partial match keeps required order", []string{"sql", "go"}, []string{"go", "k8s", "sql"}, []string{"go", "sql"}},
+		{"full match", []string{"go"}, []string{"go"}, []string{"go"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchSkills(tt.user, tt.required); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package domain
+
+type TeamMemberRename struct {
+	UserID      string
+	OldUsername string
+	NewUsername string
+}
+
+// TeamDiff описывает изменения, переводящие сохранённую команду в желаемое состояние
+type TeamDiff struct {
+	TeamName    string
+	Created     bool
+	Added       []TeamMember
+	Removed     []TeamMember
+	Renamed     []TeamMemberRename
+	Activated   []string
+	Deactivated []string
+}
+
+func (d TeamDiff) IsEmpty() bool {
+	return !d.Created &&
+		len(d.Added) == 0 &&
+		len(d.Removed) == 0 &&
+		len(d.Renamed) == 0 &&
+		len(d.Activated) == 0 &&
+		len(d.Deactivated) == 0
+}
+
+// DiffTeam сравнивает текущий состав команды с желаемым. current == nil означает, что команды ещё нет.
+// Порядок элементов в диффе совпадает с порядком участников в desired (для Removed — в current).
+func DiffTeam(current *Team, desired Team) TeamDiff {
+	diff := TeamDiff{
+		TeamName:    desired.Name,
+		Created:     current == nil,
+		Added:       []TeamMember{},
+		Removed:     []TeamMember{},
+		Renamed:     []TeamMemberRename{},
+		Activated:   []string{},
+		Deactivated: []string{},
+	}
+
+	currentMembers := make(map[string]TeamMember)
+	if current != nil {
+		for _, member := range current.Members {
+			currentMembers[member.UserID] = member
+		}
+	}
+
+	desiredIDs := make(map[string]bool, len(desired.Members))
+	for _, member := range desired.Members {
+		desiredIDs[member.UserID] = true
+
+		existing, ok := currentMembers[member.UserID]
+		if !ok {
+			diff.Added = append(diff.Added, member)
+			continue
+		}
+
+		if existing.Username != member.Username {
+			diff.Renamed = append(diff.Renamed, TeamMemberRename{
+				UserID:      member.UserID,
+				OldUsername: existing.Username,
+				NewUsername: member.Username,
+			})
+		}
+		if existing.IsActive != member.IsActive {
+			if member.IsActive {
+				diff.Activated = append(diff.Activated, member.UserID)
+			} else {
+				diff.Deactivated = append(diff.Deactivated, member.UserID)
+			}
+		}
+	}
+
+	if current != nil {
+		for _, member := range current.Members {
+			if !desiredIDs[member.UserID] {
+				diff.Removed = append(diff.Removed, member)
+			}
+		}
+	}
+
+	return diff
+}
@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffTeam(t *testing.T) {
+	alice := TeamMember{UserID: "u1", Username: "Alice", IsActive: true}
+	bob := TeamMember{UserID: "u2", Username: "Bob", IsActive: true}
+	carol := TeamMember{UserID: "u3", Username: "Carol", IsActive: false}
+
+	team := func(members ...TeamMember) Team {
+		return Team{Name: "backend", Members: members}
+	}
+	// empty — дифф без изменений, от него тесты отталкиваются
+	empty := TeamDiff{
+		TeamName:    "backend",
+		Added:       []TeamMember{},
+		Removed:     []TeamMember{},
+		Renamed:     []TeamMemberRename{},
+		Activated:   []string{},
+		Deactivated: []string{},
+	}
+	with := func(change func(d *TeamDiff)) TeamDiff {
+		d := empty
+		change(&d)
+		return d
+	}
+
+	tests := []struct {
+		name      string
+		current   *Team
+		desired   Team
+		want      TeamDiff
+		wantEmpty bool
+	}{
+		{
+			name:    "new team adds every member",
+			desired: team(alice, bob),
+			want: with(func(d *TeamDiff) {
+				d.Created = true
+				d.Added = []TeamMember{alice, bob}
+			}),
+		},
+		{
+			name:    "new team without members is still a change",
+			desired: team(),
+			want:    with(func(d *TeamDiff) { d.Created = true }),
+		},
+		{
+			name:      "same members in another order",
+			current:   &Team{Name: "backend", Members: []TeamMember{bob, alice}},
+			desired:   team(alice, bob),
+			want:      empty,
+			wantEmpty: true,
+		},
+		{
+			name:    "added and removed members",
+			current: &Team{Name: "backend", Members: []TeamMember{alice, bob}},
+			desired: team(alice, carol),
+			want: with(func(d *TeamDiff) {
+				d.Added = []TeamMember{carol}
+				d.Removed = []TeamMember{bob}
+			}),
+		},
+		{
+			name:    "removing everyone",
+			current: &Team{Name: "backend", Members: []TeamMember{alice, bob}},
+			desired: team(),
+			want:    with(func(d *TeamDiff) { d.Removed = []TeamMember{alice, bob} }),
+		},
+		{
+			name:    "rename and deactivation of the same member",
+			current: &Team{Name: "backend", Members: []TeamMember{alice}},
+			desired: team(TeamMember{UserID: "u1", Username: "Alicia", IsActive: false}),
+			want: with(func(d *TeamDiff) {
+				d.Renamed = []TeamMemberRename{{UserID: "u1", OldUsername: "Alice", NewUsername: "Alicia"}}
+				d.Deactivated = []string{"u1"}
+			}),
+		},
+		{
+			name:    "activation",
+			current: &Team{Name: "backend", Members: []TeamMember{carol}},
+			desired: team(TeamMember{UserID: "u3", Username: "Carol", IsActive: true}),
+			want:    with(func(d *TeamDiff) { d.Activated = []string{"u3"} }),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DiffTeam(tt.current, tt.desired)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+			if got.IsEmpty() != tt.wantEmpty {
+				t.Errorf("IsEmpty() = %v, want %v", got.IsEmpty(), tt.wantEmpty)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package domain
+
+import "strings"
+
+// FieldViolation описывает нарушение правила для одного поля запроса.
+// Field — путь к полю в терминах API, например "members[1].user_id".
+type FieldViolation struct {
+	Field   string
+	Message string
+}
+
+// ValidationError — ошибка с кодом VALIDATION_ERROR и списком нарушений по полям
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+func NewValidationError(violations ...FieldViolation) error {
+	return ValidationError{Violations: violations}
+}
+
+func (e ValidationError) Error() string {
+	parts := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		parts[i] = v.Field + ": " + v.Message
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+func (e ValidationError) Is(target error) bool {
+	return target == ErrorCodeValidation || target == KindInvalidInput
+}
@@ -0,0 +1,47 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// Heartbeat — проверка живости фонового воркера: воркер вызывает Beat после каждого успешного
+// запуска и Fail после неудачного, а проверка падает, если с последнего Beat прошло больше maxAge
+type Heartbeat struct {
+	maxAge  time.Duration
+	last    atomic.Int64
+	lastErr atomic.Pointer[string]
+}
+
+func NewHeartbeat(maxAge time.Duration) *Heartbeat {
+	return &Heartbeat{maxAge: maxAge}
+}
+
+func (h *Heartbeat) Beat() {
+	h.last.Store(time.Now().UnixNano())
+	h.lastErr.Store(nil)
+}
+
+// Fail запоминает ошибку запуска, не продлевая живость: если воркер падает раз за разом,
+// проверка устареет и покажет последнюю ошибку
+func (h *Heartbeat) Fail(err error) {
+	msg := err.Error()
+	h.lastErr.Store(&msg)
+}
+
+func (h *Heartbeat) Check(ctx context.Context) error {
+	last := h.last.Load()
+	if last == 0 {
+		return errors.New("worker has not started")
+	}
+	if age := time.Since(time.Unix(0, last)); age > h.maxAge {
+		if lastErr := h.lastErr.Load(); lastErr != nil {
+			return fmt.Errorf("last heartbeat %s ago, last run failed: %s", age.Round(time.Millisecond), *lastErr)
+		}
+		return fmt.Errorf("last heartbeat %s ago", age.Round(time.Millisecond))
+	}
+	return nil
+}
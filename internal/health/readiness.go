@@ -0,0 +1,139 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Checker проверяет одну зависимость сервиса; nil означает, что она работает
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+type CheckerFunc func(ctx context.Context) error
+
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// State — стадия жизненного цикла сервера
+type State int32
+
+const (
+	StateStarting State = iota
+	StateServing
+	StateStopping
+)
+
+func (s State) String() string {
+	switch s {
+	case StateStarting:
+		return "starting"
+	case StateServing:
+		return "serving"
+	case StateStopping:
+		return "stopping"
+	default:
+		return "unknown"
+	}
+}
+
+type CheckResult struct {
+	Name    string
+	Healthy bool
+	Latency time.Duration
+	Error   string
+}
+
+type Report struct {
+	Ready  bool
+	State  State
+	Checks []CheckResult
+}
+
+type namedChecker struct {
+	name    string
+	checker Checker
+}
+
+// Readiness хранит зарегистрированные проверки и стадию жизненного цикла.
+// Сервис готов, только если сервер принимает запросы и все проверки прошли.
+type Readiness struct {
+	timeout time.Duration
+	state   atomic.Int32
+
+	mu       sync.RWMutex
+	checkers []namedChecker
+}
+
+// NewReadiness создаёт Readiness в состоянии StateStarting; timeout ограничивает каждую проверку
+func NewReadiness(timeout time.Duration) *Readiness {
+	return &Readiness{timeout: timeout}
+}
+
+func (r *Readiness) Register(name string, checker Checker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checkers = append(r.checkers, namedChecker{name: name, checker: checker})
+}
+
+func (r *Readiness) SetState(state State) {
+	r.state.Store(int32(state))
+}
+
+func (r *Readiness) State() State {
+	return State(r.state.Load())
+}
+
+// Check параллельно выполняет все проверки и возвращает отчёт в порядке регистрации
+func (r *Readiness) Check(ctx context.Context) Report {
+	r.mu.RLock()
+	checkers := append([]namedChecker(nil), r.checkers...)
+	r.mu.RUnlock()
+
+	report := Report{
+		State:  r.State(),
+		Checks: make([]CheckResult, len(checkers)),
+	}
+
+	var wg sync.WaitGroup
+	for i, c := range checkers {
+		wg.Add(1)
+		go func(i int, c namedChecker) {
+			defer wg.Done()
+			report.Checks[i] = r.run(ctx, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	report.Ready = report.State == StateServing
+	for _, result := range report.Checks {
+		if !result.Healthy {
+			report.Ready = false
+		}
+	}
+
+	return report
+}
+
+func (r *Readiness) run(ctx context.Context, c namedChecker) CheckResult {
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := c.checker.Check(ctx)
+	result := CheckResult{
+		Name:    c.name,
+		Healthy: err == nil,
+		Latency: time.Since(start),
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
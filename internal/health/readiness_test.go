@@ -0,0 +1,71 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadiness(t *testing.T) {
+	readiness := NewReadiness(time.Second)
+	storageErr := error(nil)
+	readiness.Register("storage", CheckerFunc(func(context.Context) error { return storageErr }))
+	heartbeat := NewHeartbeat(time.Minute)
+	readiness.Register("worker", heartbeat)
+
+	heartbeat.Beat()
+	if report := readiness.Check(context.Background()); report.Ready || report.State != StateStarting {
+		t.Fatalf("expected not ready while starting, got %+v", report)
+	}
+
+	readiness.SetState(StateServing)
+	if report := readiness.Check(context.Background()); !report.Ready {
+		t.Fatalf("expected ready, got %+v", report)
+	}
+
+	storageErr = errors.New("connection refused")
+	report := readiness.Check(context.Background())
+	if report.Ready {
+		t.Fatalf("expected not ready with failing check, got %+v", report)
+	}
+	if report.Checks[0].Name != "storage" || report.Checks[0].Healthy || report.Checks[0].Error != "connection refused" {
+		t.Fatalf("unexpected storage result %+v", report.Checks[0])
+	}
+	if !report.Checks[1].Healthy {
+		t.Fatalf("unexpected worker result %+v", report.Checks[1])
+	}
+
+	storageErr = nil
+	readiness.SetState(StateStopping)
+	if report := readiness.Check(context.Background()); report.Ready {
+		t.Fatalf("expected not ready while stopping, got %+v", report)
+	}
+}
+
+func TestHeartbeat(t *testing.T) {
+	heartbeat := NewHeartbeat(10 * time.Millisecond)
+	if err := heartbeat.Check(context.Background()); err == nil {
+		t.Fatal("expected error before first beat")
+	}
+
+	heartbeat.Beat()
+	if err := heartbeat.Check(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if err := heartbeat.Check(context.Background()); err == nil {
+		t.Fatal("expected error for stale heartbeat")
+	}
+
+	// Неудачные запуски не продлевают живость, а в ошибке видна причина
+	heartbeat.Beat()
+	heartbeat.Fail(errors.New("storage unavailable"))
+	time.Sleep(20 * time.Millisecond)
+	err := heartbeat.Check(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "storage unavailable") {
+		t.Fatalf("expected stale heartbeat with last error, got %v", err)
+	}
+}
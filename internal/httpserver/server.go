@@ -3,18 +3,22 @@ package httpserver
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"time"
 
 	"github.com/guverz/pr-reviewer-service/internal/config"
+	"github.com/guverz/pr-reviewer-service/internal/health"
 )
 
 type Server struct {
 	httpServer      *http.Server
+	readiness       *health.Readiness
 	shutdownTimeout time.Duration
+	drainDelay      time.Duration
 }
 
-func New(cfg *config.Config, router http.Handler) (*Server, error) {
+func New(cfg *config.Config, router http.Handler, readiness *health.Readiness) (*Server, error) {
 	server := &http.Server{
 		Addr:              cfg.HTTP.Addr,
 		Handler:           router,
@@ -23,29 +27,53 @@ func New(cfg *config.Config, router http.Handler) (*Server, error) {
 
 	return &Server{
 		httpServer:      server,
+		readiness:       readiness,
 		shutdownTimeout: cfg.HTTP.ShutdownTimeout,
+		drainDelay:      cfg.HTTP.DrainDelay,
 	}, nil
 }
 
+// Serve принимает запросы до отмены ctx. Готовность выставляется только после того,
+// как сервер занял порт. При отмене ctx сервер объявляет себя неготовым, ждёт drainDelay,
+// чтобы балансировщик успел это заметить, затем перестаёт принимать соединения
+// и дожидается завершения текущих запросов не дольше shutdownTimeout.
 func (s *Server) Serve(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.httpServer.Addr)
+	if err != nil {
+		return fmt.Errorf("start http server: %w", err)
+	}
+
 	errCh := make(chan error, 1)
 
 	go func() {
-		if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			errCh <- fmt.Errorf("start http server: %w", err)
+		if err := s.httpServer.Serve(listener); err != nil && err != http.ErrServerClosed {
+			errCh <- fmt.Errorf("serve http: %w", err)
 		}
 		close(errCh)
 	}()
 
+	s.readiness.SetState(health.StateServing)
+
 	select {
 	case <-ctx.Done():
+		// drainDelay входит в shutdownTimeout
 		shutdownCtx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
 		defer cancel()
+
+		s.readiness.SetState(health.StateStopping)
+		if s.drainDelay > 0 {
+			select {
+			case <-time.After(s.drainDelay):
+			case <-shutdownCtx.Done():
+			}
+		}
+
 		if err := s.httpServer.Shutdown(shutdownCtx); err != nil {
 			return fmt.Errorf("shutdown http server: %w", err)
 		}
 		return nil
 	case err := <-errCh:
+		s.readiness.SetState(health.StateStopping)
 		return err
 	}
 }
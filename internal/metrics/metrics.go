@@ -0,0 +1,163 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/guverz/pr-reviewer-service/internal/repository"
+)
+
+const namespace = "pr_reviewer"
+
+// Причины переназначения ревьюера для метрики reviewer_reassignments_total
+const (
+	ReassignReasonManual      = "manual"
+	ReassignReasonTeamDeleted = "team_deleted"
+	ReassignReasonUnavailable = "unavailable"
+)
+
+// Metrics хранит собственный реестр и все метрики сервиса.
+// Методы безопасно вызывать на nil: тогда ничего не записывается.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+
+	prsCreated    prometheus.Counter
+	prsMerged     prometheus.Counter
+	reassignments *prometheus.CounterVec
+	noCandidate   prometheus.Counter
+
+	storageDuration *prometheus.HistogramVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Number of HTTP requests by method, route and status.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method, route and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		prsCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "pull_requests_created_total",
+			Help:      "Number of created pull requests.",
+		}),
+		prsMerged: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "pull_requests_merged_total",
+			Help:      "Number of merged pull requests.",
+		}),
+		reassignments: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reviewer_reassignments_total",
+			Help:      "Number of reviewer reassignments by reason.",
+		}, []string{"reason"}),
+		noCandidate: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reassign_no_candidate_total",
+			Help:      "Number of reassignments rejected with NO_CANDIDATE.",
+		}),
+		storageDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "storage_operation_duration_seconds",
+			Help:      "Storage operation latency by repository, operation and outcome.",
+			Buckets:   []float64{.00001, .00005, .0001, .0005, .001, .005, .01, .05, .1, .5, 1},
+		}, []string{"repository", "operation", "outcome"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.prsCreated,
+		m.prsMerged,
+		m.reassignments,
+		m.noCandidate,
+		m.storageDuration,
+	)
+
+	return m
+}
+
+// MustRegister регистрирует дополнительные коллекторы в реестре сервиса
+func (m *Metrics) MustRegister(cs ...prometheus.Collector) {
+	m.registry.MustRegister(cs...)
+}
+
+// Handler отдаёт метрики в текстовом формате Prometheus
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+func (m *Metrics) ObserveHTTP(method, route string, status int, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	code := strconv.Itoa(status)
+	m.httpRequests.WithLabelValues(method, route, code).Inc()
+	m.httpDuration.WithLabelValues(method, route, code).Observe(duration.Seconds())
+}
+
+func (m *Metrics) PullRequestCreated() {
+	if m == nil {
+		return
+	}
+	m.prsCreated.Inc()
+}
+
+func (m *Metrics) PullRequestMerged() {
+	if m == nil {
+		return
+	}
+	m.prsMerged.Inc()
+}
+
+func (m *Metrics) ReviewerReassigned(reason string) {
+	if m == nil {
+		return
+	}
+	m.reassignments.WithLabelValues(reason).Inc()
+}
+
+func (m *Metrics) NoCandidate() {
+	if m == nil {
+		return
+	}
+	m.noCandidate.Inc()
+}
+
+// ObserveStorage записывает длительность операции репозитория, начатой в start.
+// Отсутствие записи и конфликт ключа учитываются отдельно от сбоев хранилища.
+func (m *Metrics) ObserveStorage(repoName, operation string, start time.Time, err error) {
+	if m == nil {
+		return
+	}
+	outcome := "ok"
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		outcome = "not_found"
+	case errors.Is(err, repository.ErrAlreadyExists):
+		outcome = "already_exists"
+	case errors.Is(err, repository.ErrVersionConflict):
+		outcome = "version_conflict"
+	case err != nil:
+		outcome = "error"
+	}
+	m.storageDuration.WithLabelValues(repoName, operation, outcome).Observe(time.Since(start).Seconds())
+}
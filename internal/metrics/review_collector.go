@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/guverz/pr-reviewer-service/internal/domain"
+	"github.com/guverz/pr-reviewer-service/internal/repository"
+)
+
+const collectTimeout = 5 * time.Second
+
+// ReviewCollector при каждом сборе метрик считает текущее состояние ревью по открытым PR
+type ReviewCollector struct {
+	prRepo repository.PullRequestRepository
+
+	needMoreReviewers *prometheus.Desc
+	openReviews       *prometheus.Desc
+	scrapeErrors      prometheus.Counter
+}
+
+func NewReviewCollector(prRepo repository.PullRequestRepository) *ReviewCollector {
+	return &ReviewCollector{
+		prRepo: prRepo,
+		needMoreReviewers: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "pull_requests_need_more_reviewers"),
+			"Number of open pull requests with fewer than two reviewers.",
+			nil, nil,
+		),
+		openReviews: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "open_reviews"),
+			"Number of open pull requests assigned to the user for review.",
+			[]string{"user_id"}, nil,
+		),
+		scrapeErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "review_collector_errors_total",
+			Help:      "Number of failed attempts to read review state for metrics.",
+		}),
+	}
+}
+
+func (c *ReviewCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.needMoreReviewers
+	ch <- c.openReviews
+	c.scrapeErrors.Describe(ch)
+}
+
+func (c *ReviewCollector) Collect(ch chan<- prometheus.Metric) {
+	defer c.scrapeErrors.Collect(ch)
+
+	ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+	defer cancel()
+
+	status := domain.PullRequestStatusOpen
+	page, err := c.prRepo.List(ctx, domain.PullRequestFilter{
+		Status: &status,
+		SortBy: domain.PullRequestSortByID,
+		Order:  domain.SortOrderAsc,
+	})
+	if err != nil {
+		c.scrapeErrors.Inc()
+		return
+	}
+
+	needMore := 0
+	openReviews := make(map[string]int)
+	for _, pr := range page.Items {
+		if pr.NeedMoreReviewers {
+			needMore++
+		}
+		for _, reviewerID := range pr.AssignedReviewers {
+			openReviews[reviewerID]++
+		}
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.needMoreReviewers, prometheus.GaugeValue, float64(needMore))
+	for userID, count := range openReviews {
+		ch <- prometheus.MustNewConstMetric(c.openReviews, prometheus.GaugeValue, float64(count), userID)
+	}
+}
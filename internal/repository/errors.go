@@ -0,0 +1,14 @@
+package repository
+
+import "errors"
+
+// Ошибки, которые обязан возвращать любой бэкенд хранилища (возможно, обёрнутыми через %w).
+// Сервисы отличают их через errors.Is от сбоев самого хранилища.
+var (
+	// ErrNotFound — запрошенной записи нет
+	ErrNotFound = errors.New("not found")
+	// ErrAlreadyExists — запись с таким ключом уже есть
+	ErrAlreadyExists = errors.New("already exists")
+	// ErrVersionConflict — запись изменилась после чтения: версия в хранилище не совпала с ожидаемой
+	ErrVersionConflict = errors.New("version conflict")
+)
@@ -0,0 +1,116 @@
+package inmemory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/guverz/pr-reviewer-service/internal/domain"
+	"github.com/guverz/pr-reviewer-service/internal/repository"
+)
+
+type AvailabilityRepository struct {
+	mu      sync.RWMutex
+	windows map[string]*domain.Unavailability
+}
+
+func NewAvailabilityRepository() *AvailabilityRepository {
+	return &AvailabilityRepository{
+		windows: make(map[string]*domain.Unavailability),
+	}
+}
+
+func (r *AvailabilityRepository) Create(ctx context.Context, window domain.Unavailability) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.windows[window.ID]; exists {
+		return fmt.Errorf("unavailability %q: %w", window.ID, repository.ErrAlreadyExists)
+	}
+
+	windowCopy := copyUnavailability(window)
+	r.windows[window.ID] = &windowCopy
+	return nil
+}
+
+func (r *AvailabilityRepository) GetByID(ctx context.Context, id string) (*domain.Unavailability, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	window, exists := r.windows[id]
+	if !exists {
+		return nil, fmt.Errorf("unavailability %q: %w", id, repository.ErrNotFound)
+	}
+
+	windowCopy := copyUnavailability(*window)
+	return &windowCopy, nil
+}
+
+func (r *AvailabilityRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.windows[id]; !exists {
+		return fmt.Errorf("unavailability %q: %w", id, repository.ErrNotFound)
+	}
+
+	delete(r.windows, id)
+	return nil
+}
+
+func (r *AvailabilityRepository) ListByUser(ctx context.Context, userID string) ([]domain.Unavailability, error) {
+	return r.list(func(window *domain.Unavailability) bool {
+		return window.UserID == userID
+	}), nil
+}
+
+func (r *AvailabilityRepository) ListActive(ctx context.Context, at time.Time) ([]domain.Unavailability, error) {
+	return r.list(func(window *domain.Unavailability) bool {
+		return window.Covers(at)
+	}), nil
+}
+
+func (r *AvailabilityRepository) MarkReviewsReassigned(ctx context.Context, id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	window, exists := r.windows[id]
+	if !exists {
+		return fmt.Errorf("unavailability %q: %w", id, repository.ErrNotFound)
+	}
+
+	window.ReviewsReassignedAt = &at
+	return nil
+}
+
+// list возвращает копии подходящих интервалов по возрастанию StartsAt
+func (r *AvailabilityRepository) list(match func(window *domain.Unavailability) bool) []domain.Unavailability {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	windows := make([]domain.Unavailability, 0)
+	for _, window := range r.windows {
+		if match(window) {
+			windows = append(windows, copyUnavailability(*window))
+		}
+	}
+
+	sort.Slice(windows, func(i, j int) bool {
+		if !windows[i].StartsAt.Equal(windows[j].StartsAt) {
+			return windows[i].StartsAt.Before(windows[j].StartsAt)
+		}
+		return windows[i].ID < windows[j].ID
+	})
+	return windows
+}
+
+func copyUnavailability(window domain.Unavailability) domain.Unavailability {
+	windowCopy := window
+	if window.ReviewsReassignedAt != nil {
+		reassignedAt := *window.ReviewsReassignedAt
+		windowCopy.ReviewsReassignedAt = &reassignedAt
+	}
+	return windowCopy
+}
@@ -0,0 +1,109 @@
+package inmemory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/guverz/pr-reviewer-service/internal/domain"
+	"github.com/guverz/pr-reviewer-service/internal/repository"
+)
+
+type idempotencyKey struct {
+	key   string
+	route string
+}
+
+type IdempotencyRepository struct {
+	mu      sync.Mutex
+	records map[idempotencyKey]*domain.IdempotencyRecord
+}
+
+func NewIdempotencyRepository() *IdempotencyRepository {
+	return &IdempotencyRepository{
+		records: make(map[idempotencyKey]*domain.IdempotencyRecord),
+	}
+}
+
+func (r *IdempotencyRepository) Reserve(ctx context.Context, record domain.IdempotencyRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := idempotencyKey{key: record.Key, route: record.Route}
+	if existing, exists := r.records[id]; exists && existing.ExpiresAt.After(record.CreatedAt) {
+		return fmt.Errorf("idempotency key %q: %w", record.Key, repository.ErrAlreadyExists)
+	}
+
+	recordCopy := copyIdempotencyRecord(record)
+	r.records[id] = &recordCopy
+	return nil
+}
+
+func (r *IdempotencyRepository) Get(ctx context.Context, key, route string) (*domain.IdempotencyRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record, exists := r.records[idempotencyKey{key: key, route: route}]
+	if !exists || !record.ExpiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("idempotency key %q: %w", key, repository.ErrNotFound)
+	}
+
+	recordCopy := copyIdempotencyRecord(*record)
+	return &recordCopy, nil
+}
+
+func (r *IdempotencyRepository) Complete(ctx context.Context, key, route string, response domain.IdempotentResponse) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record, exists := r.records[idempotencyKey{key: key, route: route}]
+	if !exists {
+		return fmt.Errorf("idempotency key %q: %w", key, repository.ErrNotFound)
+	}
+
+	responseCopy := copyIdempotentResponse(response)
+	record.Response = &responseCopy
+	return nil
+}
+
+func (r *IdempotencyRepository) Delete(ctx context.Context, key, route string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.records, idempotencyKey{key: key, route: route})
+	return nil
+}
+
+func (r *IdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deleted := 0
+	for id, record := range r.records {
+		if !record.ExpiresAt.After(now) {
+			delete(r.records, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func copyIdempotencyRecord(record domain.IdempotencyRecord) domain.IdempotencyRecord {
+	recordCopy := record
+	if record.Response != nil {
+		responseCopy := copyIdempotentResponse(*record.Response)
+		recordCopy.Response = &responseCopy
+	}
+	return recordCopy
+}
+
+func copyIdempotentResponse(response domain.IdempotentResponse) domain.IdempotentResponse {
+	responseCopy := response
+	responseCopy.Header = make(map[string]string, len(response.Header))
+	for name, value := range response.Header {
+		responseCopy.Header[name] = value
+	}
+	responseCopy.Body = append([]byte(nil), response.Body...)
+	return responseCopy
+}
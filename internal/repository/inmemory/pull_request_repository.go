@@ -2,10 +2,13 @@ package inmemory
 
 import (
 	"context"
-	"errors"
+	"fmt"
+	"slices"
+	"sort"
 	"sync"
 
 	"github.com/guverz/pr-reviewer-service/internal/domain"
+	"github.com/guverz/pr-reviewer-service/internal/repository"
 )
 
 type PullRequestRepository struct {
@@ -24,15 +27,12 @@ func (r *PullRequestRepository) Create(ctx context.Context, pr domain.PullReques
 	defer r.mu.Unlock()
 
 	if _, exists := r.prs[pr.ID]; exists {
-		return errors.New("PR already exists")
+		return fmt.Errorf("pull request %q: %w", pr.ID, repository.ErrAlreadyExists)
 	}
 
 	// Создаём копию PR
-	prCopy := pr
-	reviewersCopy := make([]string, len(pr.AssignedReviewers))
-	copy(reviewersCopy, pr.AssignedReviewers)
-	prCopy.AssignedReviewers = reviewersCopy
-
+	prCopy := copyPullRequest(pr)
+	prCopy.Version = 1
 	r.prs[pr.ID] = &prCopy
 	return nil
 }
@@ -43,15 +43,11 @@ func (r *PullRequestRepository) GetByID(ctx context.Context, prID string) (*doma
 
 	pr, exists := r.prs[prID]
 	if !exists {
-		return nil, errors.New("PR not found")
+		return nil, fmt.Errorf("pull request %q: %w", prID, repository.ErrNotFound)
 	}
 
 	// Возвращаем копию
-	prCopy := *pr
-	reviewersCopy := make([]string, len(pr.AssignedReviewers))
-	copy(reviewersCopy, pr.AssignedReviewers)
-	prCopy.AssignedReviewers = reviewersCopy
-
+	prCopy := copyPullRequest(*pr)
 	return &prCopy, nil
 }
 
@@ -59,20 +55,46 @@ func (r *PullRequestRepository) Update(ctx context.Context, pr domain.PullReques
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, exists := r.prs[pr.ID]; !exists {
-		return errors.New("PR not found")
+	stored, exists := r.prs[pr.ID]
+	if !exists {
+		return fmt.Errorf("pull request %q: %w", pr.ID, repository.ErrNotFound)
+	}
+	if stored.Version != pr.Version {
+		return fmt.Errorf("pull request %q: version %d, expected %d: %w",
+			pr.ID, stored.Version, pr.Version, repository.ErrVersionConflict)
 	}
 
 	// Обновляем PR
-	prCopy := pr
-	reviewersCopy := make([]string, len(pr.AssignedReviewers))
-	copy(reviewersCopy, pr.AssignedReviewers)
-	prCopy.AssignedReviewers = reviewersCopy
-
+	prCopy := copyPullRequest(pr)
+	prCopy.Version++
 	r.prs[pr.ID] = &prCopy
 	return nil
 }
 
+func (r *PullRequestRepository) UpdateMany(ctx context.Context, prs []domain.PullRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Сначала проверяем версии всех PR, чтобы конфликт не оставил часть из них обновлённой
+	for _, pr := range prs {
+		stored, exists := r.prs[pr.ID]
+		if !exists {
+			return fmt.Errorf("pull request %q: %w", pr.ID, repository.ErrNotFound)
+		}
+		if stored.Version != pr.Version {
+			return fmt.Errorf("pull request %q: version %d, expected %d: %w",
+				pr.ID, stored.Version, pr.Version, repository.ErrVersionConflict)
+		}
+	}
+
+	for _, pr := range prs {
+		prCopy := copyPullRequest(pr)
+		prCopy.Version++
+		r.prs[pr.ID] = &prCopy
+	}
+	return nil
+}
+
 func (r *PullRequestRepository) ListByReviewer(ctx context.Context, reviewerID string) ([]domain.PullRequest, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -80,16 +102,144 @@ func (r *PullRequestRepository) ListByReviewer(ctx context.Context, reviewerID s
 	prs := make([]domain.PullRequest, 0)
 	for _, pr := range r.prs {
 		if pr.HasReviewer(reviewerID) {
-			prCopy := *pr
-			reviewersCopy := make([]string, len(pr.AssignedReviewers))
-			copy(reviewersCopy, pr.AssignedReviewers)
-			prCopy.AssignedReviewers = reviewersCopy
-			prs = append(prs, prCopy)
+			prs = append(prs, copyPullRequest(*pr))
 		}
 	}
 
 	return prs, nil
 }
 
+func (r *PullRequestRepository) CountOpenReviews(ctx context.Context, reviewerIDs []string) (map[string]int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	wanted := make(map[string]bool, len(reviewerIDs))
+	for _, reviewerID := range reviewerIDs {
+		wanted[reviewerID] = true
+	}
+
+	counts := make(map[string]int)
+	for _, pr := range r.prs {
+		if pr.IsMerged() {
+			continue
+		}
+		for _, reviewerID := range pr.AssignedReviewers {
+			if wanted[reviewerID] {
+				counts[reviewerID]++
+			}
+		}
+	}
+
+	return counts, nil
+}
+
+func (r *PullRequestRepository) List(ctx context.Context, filter domain.PullRequestFilter) (*domain.PullRequestPage, error) {
+	r.mu.RLock()
+	matched := make([]domain.PullRequest, 0)
+	for _, pr := range r.prs {
+		if matchesFilter(pr, filter) {
+			matched = append(matched, copyPullRequest(*pr))
+		}
+	}
+	r.mu.RUnlock()
+
+	desc := filter.Order == domain.SortOrderDesc
+	less := func(aValue int64, aID string, bValue int64, bID string) bool {
+		if aValue != bValue {
+			if desc {
+				return aValue > bValue
+			}
+			return aValue < bValue
+		}
+		if desc {
+			return aID > bID
+		}
+		return aID < bID
+	}
 
+	sort.Slice(matched, func(i, j int) bool {
+		return less(
+			matched[i].SortValue(filter.SortBy), matched[i].ID,
+			matched[j].SortValue(filter.SortBy), matched[j].ID,
+		)
+	})
+
+	// Пропускаем всё, что находится не дальше курсора
+	start := 0
+	if filter.After != nil {
+		start = sort.Search(len(matched), func(i int) bool {
+			return less(filter.After.SortValue, filter.After.ID, matched[i].SortValue(filter.SortBy), matched[i].ID)
+		})
+	}
+	matched = matched[start:]
+
+	page := &domain.PullRequestPage{Items: matched}
+	if filter.Limit > 0 && len(matched) > filter.Limit {
+		page.Items = matched[:filter.Limit]
+		last := page.Items[len(page.Items)-1]
+		page.Next = &domain.PageCursor{
+			SortBy:    filter.SortBy,
+			Order:     filter.Order,
+			SortValue: last.SortValue(filter.SortBy),
+			ID:        last.ID,
+		}
+	}
+
+	return page, nil
+}
+
+func matchesFilter(pr *domain.PullRequest, filter domain.PullRequestFilter) bool {
+	if filter.Status != nil && pr.Status != *filter.Status {
+		return false
+	}
+	if filter.AuthorIDs != nil && !slices.Contains(filter.AuthorIDs, pr.AuthorID) {
+		return false
+	}
+	if filter.ReviewerID != "" && !pr.HasReviewer(filter.ReviewerID) {
+		return false
+	}
+	if filter.NeedMoreReviewers != nil && pr.NeedMoreReviewers != *filter.NeedMoreReviewers {
+		return false
+	}
+	if filter.CreatedFrom != nil && pr.CreatedAt.Before(*filter.CreatedFrom) {
+		return false
+	}
+	if filter.CreatedTo != nil && pr.CreatedAt.After(*filter.CreatedTo) {
+		return false
+	}
+	if filter.MergedFrom != nil || filter.MergedTo != nil {
+		if pr.MergedAt == nil {
+			return false
+		}
+		if filter.MergedFrom != nil && pr.MergedAt.Before(*filter.MergedFrom) {
+			return false
+		}
+		if filter.MergedTo != nil && pr.MergedAt.After(*filter.MergedTo) {
+			return false
+		}
+	}
+	return true
+}
 
+// copyPullRequest делает глубокую копию PR, чтобы хранилище не разделяло срезы с вызывающим
+func copyPullRequest(pr domain.PullRequest) domain.PullRequest {
+	prCopy := pr
+	prCopy.AssignedReviewers = make([]string, len(pr.AssignedReviewers))
+	copy(prCopy.AssignedReviewers, pr.AssignedReviewers)
+	if pr.Reassignments != nil {
+		prCopy.Reassignments = make([]domain.ReviewerReassignment, len(pr.Reassignments))
+		copy(prCopy.Reassignments, pr.Reassignments)
+	}
+	prCopy.RequiredSkills = slices.Clone(pr.RequiredSkills)
+	if pr.MatchedSkills != nil {
+		prCopy.MatchedSkills = make(map[string][]string, len(pr.MatchedSkills))
+		for reviewerID, skills := range pr.MatchedSkills {
+			prCopy.MatchedSkills[reviewerID] = slices.Clone(skills)
+		}
+	}
+	if pr.MergedAt != nil {
+		mergedAt := *pr.MergedAt
+		prCopy.MergedAt = &mergedAt
+	}
+	return prCopy
+}
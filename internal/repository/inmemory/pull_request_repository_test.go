@@ -0,0 +1,116 @@
+package inmemory
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/guverz/pr-reviewer-service/internal/domain"
+)
+
+func TestListPagination(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	merged := base.Add(time.Hour)
+
+	repo := NewPullRequestRepository()
+	// pr-a и pr-b созданы одновременно: порядок между ними задаёт ID
+	for _, pr := range []domain.PullRequest{
+		{ID: "pr-b", CreatedAt: base},
+		{ID: "pr-a", CreatedAt: base},
+		{ID: "pr-c", CreatedAt: base.Add(time.Minute), MergedAt: &merged},
+		{ID: "pr-d", CreatedAt: base.Add(2 * time.Minute)},
+	} {
+		if err := repo.Create(context.Background(), pr); err != nil {
+			t.Fatalf("create %s: %v", pr.ID, err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter domain.PullRequestFilter
+		want   []string
+		pages  int
+	}{
+		{
+			name:   "asc with ties on sort value",
+			filter: domain.PullRequestFilter{SortBy: domain.PullRequestSortByCreatedAt, Order: domain.SortOrderAsc, Limit: 1},
+			want:   []string{"pr-a", "pr-b", "pr-c", "pr-d"},
+			pages:  4,
+		},
+		{
+			name:   "desc reverses the id tie-break",
+			filter: domain.PullRequestFilter{SortBy: domain.PullRequestSortByCreatedAt, Order: domain.SortOrderDesc, Limit: 3},
+			want:   []string{"pr-d", "pr-c", "pr-b", "pr-a"},
+			pages:  2,
+		},
+		{
+			name:   "limit equal to total has no next page",
+			filter: domain.PullRequestFilter{SortBy: domain.PullRequestSortByID, Order: domain.SortOrderAsc, Limit: 4},
+			want:   []string{"pr-a", "pr-b", "pr-c", "pr-d"},
+			pages:  1,
+		},
+		{
+			name:   "no limit returns everything",
+			filter: domain.PullRequestFilter{SortBy: domain.PullRequestSortByID, Order: domain.SortOrderDesc},
+			want:   []string{"pr-d", "pr-c", "pr-b", "pr-a"},
+			pages:  1,
+		},
+		{
+			name:   "missing merged_at sorts as zero",
+			filter: domain.PullRequestFilter{SortBy: domain.PullRequestSortByMergedAt, Order: domain.SortOrderDesc, Limit: 2},
+			want:   []string{"pr-c", "pr-d", "pr-b", "pr-a"},
+			pages:  2,
+		},
+		{
+			name: "cursor of a deleted item resumes after its position",
+			filter: domain.PullRequestFilter{
+				SortBy: domain.PullRequestSortByID, Order: domain.SortOrderAsc, Limit: 10,
+				After: &domain.PageCursor{SortBy: domain.PullRequestSortByID, ID: "pr-bb"},
+			},
+			want:  []string{"pr-c", "pr-d"},
+			pages: 1,
+		},
+		{
+			name: "cursor past the end returns an empty page",
+			filter: domain.PullRequestFilter{
+				SortBy: domain.PullRequestSortByID, Order: domain.SortOrderAsc, Limit: 10,
+				After: &domain.PageCursor{SortBy: domain.PullRequestSortByID, ID: "pr-z"},
+			},
+			want:  []string{},
+			pages: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := tt.filter
+			got := make([]string, 0)
+			pages := 0
+			for {
+				page, err := repo.List(context.Background(), filter)
+				if err != nil {
+					t.Fatalf("list: %v", err)
+				}
+				pages++
+				for _, pr := range page.Items {
+					got = append(got, pr.ID)
+				}
+				if page.Next == nil {
+					break
+				}
+				if len(page.Items) != filter.Limit {
+					t.Fatalf("page %d has %d items and a next cursor", pages, len(page.Items))
+				}
+				filter.After = page.Next
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+			if pages != tt.pages {
+				t.Errorf("got %d pages, want %d", pages, tt.pages)
+			}
+		})
+	}
+}
@@ -1,27 +1,46 @@
 package inmemory
 
 import (
+	"context"
+
 	"github.com/guverz/pr-reviewer-service/internal/repository"
 )
 
 type Repositories struct {
-	Team        repository.TeamRepository
-	User        repository.UserRepository
-	PullRequest repository.PullRequestRepository
-	Transaction repository.TransactionManager
+	Team         repository.TeamRepository
+	User         repository.UserRepository
+	PullRequest  repository.PullRequestRepository
+	Role         repository.RoleRepository
+	Availability repository.AvailabilityRepository
+	Rotation     repository.RotationRepository
+	Idempotency  repository.IdempotencyRepository
+	Transaction  repository.TransactionManager
 }
 
 func NewRepositories() *Repositories {
 	teamRepo := NewTeamRepository()
 	userRepo := NewUserRepository()
 	prRepo := NewPullRequestRepository()
+	roleRepo := NewRoleRepository()
+	availabilityRepo := NewAvailabilityRepository()
+	rotationRepo := NewRotationRepository()
+	idempotencyRepo := NewIdempotencyRepository()
 	txMgr := NewTransactionManager()
 
 	return &Repositories{
-		Team:        teamRepo,
-		User:        userRepo,
-		PullRequest: prRepo,
-		Transaction: txMgr,
+		Team:         teamRepo,
+		User:         userRepo,
+		PullRequest:  prRepo,
+		Role:         roleRepo,
+		Availability: availabilityRepo,
+		Rotation:     rotationRepo,
+		Idempotency:  idempotencyRepo,
+		Transaction:  txMgr,
 	}
 }
 
+// Close освобождает ресурсы хранилища. In-memory хранилищу закрывать нечего,
+// метод нужен, чтобы приложение останавливало любое хранилище одинаково.
+func (r *Repositories) Close(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,112 @@
+package inmemory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/guverz/pr-reviewer-service/internal/domain"
+	"github.com/guverz/pr-reviewer-service/internal/repository"
+)
+
+type RoleRepository struct {
+	mu          sync.RWMutex
+	assignments map[domain.RoleAssignment]struct{}
+}
+
+func NewRoleRepository() *RoleRepository {
+	return &RoleRepository{
+		assignments: make(map[domain.RoleAssignment]struct{}),
+	}
+}
+
+// Assign выдаёт роль; повторная выдача той же роли ничего не меняет
+func (r *RoleRepository) Assign(ctx context.Context, assignment domain.RoleAssignment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.assignments[assignment] = struct{}{}
+	return nil
+}
+
+func (r *RoleRepository) Revoke(ctx context.Context, assignment domain.RoleAssignment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.assignments[assignment]; !exists {
+		return fmt.Errorf("role %s of user %q: %w", assignment.Role, assignment.UserID, repository.ErrNotFound)
+	}
+
+	delete(r.assignments, assignment)
+	return nil
+}
+
+func (r *RoleRepository) ListByUser(ctx context.Context, userID string) ([]domain.RoleAssignment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	assignments := make([]domain.RoleAssignment, 0)
+	for assignment := range r.assignments {
+		if assignment.UserID == userID {
+			assignments = append(assignments, assignment)
+		}
+	}
+
+	sortAssignments(assignments)
+	return assignments, nil
+}
+
+func (r *RoleRepository) List(ctx context.Context) ([]domain.RoleAssignment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	assignments := make([]domain.RoleAssignment, 0, len(r.assignments))
+	for assignment := range r.assignments {
+		assignments = append(assignments, assignment)
+	}
+
+	sortAssignments(assignments)
+	return assignments, nil
+}
+
+func (r *RoleRepository) RenameTeam(ctx context.Context, oldName, newName string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for assignment := range r.assignments {
+		if assignment.TeamName == oldName {
+			delete(r.assignments, assignment)
+			assignment.TeamName = newName
+			r.assignments[assignment] = struct{}{}
+		}
+	}
+
+	return nil
+}
+
+func (r *RoleRepository) DeleteByTeam(ctx context.Context, teamName string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for assignment := range r.assignments {
+		if assignment.TeamName == teamName {
+			delete(r.assignments, assignment)
+		}
+	}
+
+	return nil
+}
+
+func sortAssignments(assignments []domain.RoleAssignment) {
+	sort.Slice(assignments, func(i, j int) bool {
+		a, b := assignments[i], assignments[j]
+		if a.UserID != b.UserID {
+			return a.UserID < b.UserID
+		}
+		if a.Role != b.Role {
+			return a.Role < b.Role
+		}
+		return a.TeamName < b.TeamName
+	})
+}
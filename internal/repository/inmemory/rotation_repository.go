@@ -0,0 +1,70 @@
+package inmemory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/guverz/pr-reviewer-service/internal/domain"
+	"github.com/guverz/pr-reviewer-service/internal/repository"
+)
+
+type RotationRepository struct {
+	mu        sync.RWMutex
+	rotations map[string]domain.ReviewerRotation
+}
+
+func NewRotationRepository() *RotationRepository {
+	return &RotationRepository{
+		rotations: make(map[string]domain.ReviewerRotation),
+	}
+}
+
+func (r *RotationRepository) Get(ctx context.Context, teamName string) (*domain.ReviewerRotation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rotation, exists := r.rotations[teamName]
+	if !exists {
+		return nil, fmt.Errorf("rotation %q: %w", teamName, repository.ErrNotFound)
+	}
+	return &rotation, nil
+}
+
+func (r *RotationRepository) Save(ctx context.Context, rotation domain.ReviewerRotation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Отсутствующая запись соответствует версии 0
+	stored := r.rotations[rotation.TeamName]
+	if stored.Version != rotation.Version {
+		return fmt.Errorf("rotation %q: version %d, expected %d: %w",
+			rotation.TeamName, stored.Version, rotation.Version, repository.ErrVersionConflict)
+	}
+
+	rotation.Version++
+	r.rotations[rotation.TeamName] = rotation
+	return nil
+}
+
+func (r *RotationRepository) RenameTeam(ctx context.Context, oldName, newName string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rotation, exists := r.rotations[oldName]
+	if !exists {
+		return nil
+	}
+	delete(r.rotations, oldName)
+	rotation.TeamName = newName
+	r.rotations[newName] = rotation
+	return nil
+}
+
+func (r *RotationRepository) DeleteByTeam(ctx context.Context, teamName string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.rotations, teamName)
+	return nil
+}
@@ -2,10 +2,12 @@ package inmemory
 
 import (
 	"context"
-	"errors"
+	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/guverz/pr-reviewer-service/internal/domain"
+	"github.com/guverz/pr-reviewer-service/internal/repository"
 )
 
 type TeamRepository struct {
@@ -24,7 +26,7 @@ func (r *TeamRepository) Create(ctx context.Context, team domain.Team) error {
 	defer r.mu.Unlock()
 
 	if _, exists := r.teams[team.Name]; exists {
-		return errors.New("team already exists")
+		return fmt.Errorf("team %q: %w", team.Name, repository.ErrAlreadyExists)
 	}
 
 	// Создаём копию команды
@@ -39,7 +41,7 @@ func (r *TeamRepository) GetByName(ctx context.Context, teamName string) (*domai
 
 	team, exists := r.teams[teamName]
 	if !exists {
-		return nil, errors.New("team not found")
+		return nil, fmt.Errorf("team %q: %w", teamName, repository.ErrNotFound)
 	}
 
 	// Возвращаем копию
@@ -51,6 +53,90 @@ func (r *TeamRepository) GetByName(ctx context.Context, teamName string) (*domai
 	return &teamCopy, nil
 }
 
+func (r *TeamRepository) SetMembers(ctx context.Context, teamName string, members []domain.TeamMember) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	team, exists := r.teams[teamName]
+	if !exists {
+		return fmt.Errorf("team %q: %w", teamName, repository.ErrNotFound)
+	}
+
+	membersCopy := make([]domain.TeamMember, len(members))
+	copy(membersCopy, members)
+	team.Members = membersCopy
+	return nil
+}
+
+func (r *TeamRepository) RemoveMember(ctx context.Context, teamName string, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	team, exists := r.teams[teamName]
+	if !exists {
+		return fmt.Errorf("team %q: %w", teamName, repository.ErrNotFound)
+	}
+
+	for i := range team.Members {
+		if team.Members[i].UserID == userID {
+			team.Members = append(team.Members[:i:i], team.Members[i+1:]...)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("member %q of team %q: %w", userID, teamName, repository.ErrNotFound)
+}
+
+func (r *TeamRepository) List(ctx context.Context) ([]domain.Team, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	teams := make([]domain.Team, 0, len(r.teams))
+	for _, team := range r.teams {
+		teamCopy := *team
+		membersCopy := make([]domain.TeamMember, len(team.Members))
+		copy(membersCopy, team.Members)
+		teamCopy.Members = membersCopy
+		teams = append(teams, teamCopy)
+	}
+
+	sort.Slice(teams, func(i, j int) bool {
+		return teams[i].Name < teams[j].Name
+	})
+
+	return teams, nil
+}
+
+func (r *TeamRepository) Rename(ctx context.Context, oldName, newName string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	team, exists := r.teams[oldName]
+	if !exists {
+		return fmt.Errorf("team %q: %w", oldName, repository.ErrNotFound)
+	}
+	if _, exists := r.teams[newName]; exists {
+		return fmt.Errorf("team %q: %w", newName, repository.ErrAlreadyExists)
+	}
+
+	team.Name = newName
+	r.teams[newName] = team
+	delete(r.teams, oldName)
+	return nil
+}
+
+func (r *TeamRepository) Delete(ctx context.Context, teamName string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.teams[teamName]; !exists {
+		return fmt.Errorf("team %q: %w", teamName, repository.ErrNotFound)
+	}
+
+	delete(r.teams, teamName)
+	return nil
+}
+
 // UpdateMember обновляет статус активности участника команды
 func (r *TeamRepository) UpdateMember(ctx context.Context, teamName string, userID string, isActive bool) error {
 	r.mu.Lock()
@@ -58,7 +144,7 @@ func (r *TeamRepository) UpdateMember(ctx context.Context, teamName string, user
 
 	team, exists := r.teams[teamName]
 	if !exists {
-		return errors.New("team not found")
+		return fmt.Errorf("team %q: %w", teamName, repository.ErrNotFound)
 	}
 
 	// Ищем участника в команде и обновляем его статус
@@ -69,5 +155,5 @@ func (r *TeamRepository) UpdateMember(ctx context.Context, teamName string, user
 		}
 	}
 
-	return errors.New("member not found in team")
+	return fmt.Errorf("member %q of team %q: %w", userID, teamName, repository.ErrNotFound)
 }
@@ -1,17 +1,31 @@
 package inmemory
 
-import "context"
+import (
+	"context"
+	"sync"
+)
 
-type TransactionManager struct{}
+type txKey struct{}
+
+// TransactionManager выполняет транзакции по очереди: проверка и запись внутри одной
+// транзакции не перемежаются с другими транзакциями. Отката при ошибке нет.
+type TransactionManager struct {
+	mu sync.Mutex
+}
 
 func NewTransactionManager() *TransactionManager {
 	return &TransactionManager{}
 }
 
-// WithinTransaction выполняет функцию в "транзакции" (для in-memory просто выполняет функцию)
+// WithinTransaction выполняет функцию под общей блокировкой; вложенный вызов
+// выполняется в уже открытой транзакции
 func (tm *TransactionManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
-	return fn(ctx)
-}
-
+	if ctx.Value(txKey{}) != nil {
+		return fn(ctx)
+	}
 
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
 
+	return fn(context.WithValue(ctx, txKey{}, true))
+}
@@ -2,10 +2,13 @@ package inmemory
 
 import (
 	"context"
-	"errors"
+	"fmt"
+	"slices"
+	"sort"
 	"sync"
 
 	"github.com/guverz/pr-reviewer-service/internal/domain"
+	"github.com/guverz/pr-reviewer-service/internal/repository"
 )
 
 type UserRepository struct {
@@ -30,6 +33,11 @@ func (r *UserRepository) UpsertTeamMembers(ctx context.Context, teamName string,
 			TeamName: teamName,
 			IsActive: member.IsActive,
 		}
+		// Лимит ревью и теги экспертизы задаются отдельно и не входят в состав команды
+		if existing, exists := r.users[member.UserID]; exists {
+			user.MaxOpenReviews = existing.MaxOpenReviews
+			user.Skills = existing.Skills
+		}
 		r.users[member.UserID] = user
 	}
 
@@ -42,7 +50,7 @@ func (r *UserRepository) GetByID(ctx context.Context, userID string) (*domain.Us
 
 	user, exists := r.users[userID]
 	if !exists {
-		return nil, errors.New("user not found")
+		return nil, fmt.Errorf("user %q: %w", userID, repository.ErrNotFound)
 	}
 
 	// Возвращаем копию
@@ -50,13 +58,27 @@ func (r *UserRepository) GetByID(ctx context.Context, userID string) (*domain.Us
 	return &userCopy, nil
 }
 
+func (r *UserRepository) GetByIDs(ctx context.Context, userIDs []string) (map[string]domain.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := make(map[string]domain.User, len(userIDs))
+	for _, userID := range userIDs {
+		if user, exists := r.users[userID]; exists {
+			users[userID] = *user
+		}
+	}
+
+	return users, nil
+}
+
 func (r *UserRepository) SetActive(ctx context.Context, userID string, isActive bool) (*domain.User, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	user, exists := r.users[userID]
 	if !exists {
-		return nil, errors.New("user not found")
+		return nil, fmt.Errorf("user %q: %w", userID, repository.ErrNotFound)
 	}
 
 	// Обновляем флаг активности
@@ -67,6 +89,37 @@ func (r *UserRepository) SetActive(ctx context.Context, userID string, isActive
 	return &userCopy, nil
 }
 
+func (r *UserRepository) SetMaxOpenReviews(ctx context.Context, userID string, limit int) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, exists := r.users[userID]
+	if !exists {
+		return nil, fmt.Errorf("user %q: %w", userID, repository.ErrNotFound)
+	}
+
+	user.MaxOpenReviews = limit
+
+	userCopy := *user
+	return &userCopy, nil
+}
+
+func (r *UserRepository) SetSkills(ctx context.Context, userID string, skills []string) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, exists := r.users[userID]
+	if !exists {
+		return nil, fmt.Errorf("user %q: %w", userID, repository.ErrNotFound)
+	}
+
+	// Копия не даёт вызывающему менять теги в хранилище через свой срез
+	user.Skills = slices.Clone(skills)
+
+	userCopy := *user
+	return &userCopy, nil
+}
+
 func (r *UserRepository) ListByTeam(ctx context.Context, teamName string, onlyActive bool) ([]domain.User, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -84,5 +137,44 @@ func (r *UserRepository) ListByTeam(ctx context.Context, teamName string, onlyAc
 	return users, nil
 }
 
+func (r *UserRepository) List(ctx context.Context) ([]domain.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := make([]domain.User, 0, len(r.users))
+	for _, user := range r.users {
+		users = append(users, *user)
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].ID < users[j].ID
+	})
+
+	return users, nil
+}
+
+func (r *UserRepository) RenameTeam(ctx context.Context, oldName, newName string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, user := range r.users {
+		if user.TeamName == oldName {
+			user.TeamName = newName
+		}
+	}
+
+	return nil
+}
+
+func (r *UserRepository) RemoveFromTeam(ctx context.Context, teamName string, userIDs []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
+	for _, userID := range userIDs {
+		if user, exists := r.users[userID]; exists && user.TeamName == teamName {
+			user.TeamName = ""
+		}
+	}
 
+	return nil
+}
@@ -0,0 +1,65 @@
+package instrumented
+
+import (
+	"context"
+	"time"
+
+	"github.com/guverz/pr-reviewer-service/internal/domain"
+	"github.com/guverz/pr-reviewer-service/internal/metrics"
+	"github.com/guverz/pr-reviewer-service/internal/repository"
+)
+
+type availabilityRepository struct {
+	next    repository.AvailabilityRepository
+	metrics *metrics.Metrics
+}
+
+func NewAvailabilityRepository(next repository.AvailabilityRepository, m *metrics.Metrics) repository.AvailabilityRepository {
+	return &availabilityRepository{next: next, metrics: m}
+}
+
+func (r *availabilityRepository) start(ctx context.Context, operation string) (context.Context, func(error)) {
+	return startOperation(ctx, r.metrics, "availability", operation)
+}
+
+func (r *availabilityRepository) Create(ctx context.Context, window domain.Unavailability) error {
+	ctx, done := r.start(ctx, "create")
+	err := r.next.Create(ctx, window)
+	done(err)
+	return err
+}
+
+func (r *availabilityRepository) GetByID(ctx context.Context, id string) (*domain.Unavailability, error) {
+	ctx, done := r.start(ctx, "get_by_id")
+	window, err := r.next.GetByID(ctx, id)
+	done(err)
+	return window, err
+}
+
+func (r *availabilityRepository) Delete(ctx context.Context, id string) error {
+	ctx, done := r.start(ctx, "delete")
+	err := r.next.Delete(ctx, id)
+	done(err)
+	return err
+}
+
+func (r *availabilityRepository) ListByUser(ctx context.Context, userID string) ([]domain.Unavailability, error) {
+	ctx, done := r.start(ctx, "list_by_user")
+	windows, err := r.next.ListByUser(ctx, userID)
+	done(err)
+	return windows, err
+}
+
+func (r *availabilityRepository) ListActive(ctx context.Context, at time.Time) ([]domain.Unavailability, error) {
+	ctx, done := r.start(ctx, "list_active")
+	windows, err := r.next.ListActive(ctx, at)
+	done(err)
+	return windows, err
+}
+
+func (r *availabilityRepository) MarkReviewsReassigned(ctx context.Context, id string, at time.Time) error {
+	ctx, done := r.start(ctx, "mark_reviews_reassigned")
+	err := r.next.MarkReviewsReassigned(ctx, id, at)
+	done(err)
+	return err
+}
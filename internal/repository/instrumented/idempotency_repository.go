@@ -0,0 +1,58 @@
+package instrumented
+
+import (
+	"context"
+	"time"
+
+	"github.com/guverz/pr-reviewer-service/internal/domain"
+	"github.com/guverz/pr-reviewer-service/internal/metrics"
+	"github.com/guverz/pr-reviewer-service/internal/repository"
+)
+
+type idempotencyRepository struct {
+	next    repository.IdempotencyRepository
+	metrics *metrics.Metrics
+}
+
+func NewIdempotencyRepository(next repository.IdempotencyRepository, m *metrics.Metrics) repository.IdempotencyRepository {
+	return &idempotencyRepository{next: next, metrics: m}
+}
+
+func (r *idempotencyRepository) start(ctx context.Context, operation string) (context.Context, func(error)) {
+	return startOperation(ctx, r.metrics, "idempotency", operation)
+}
+
+func (r *idempotencyRepository) Reserve(ctx context.Context, record domain.IdempotencyRecord) error {
+	ctx, done := r.start(ctx, "reserve")
+	err := r.next.Reserve(ctx, record)
+	done(err)
+	return err
+}
+
+func (r *idempotencyRepository) Get(ctx context.Context, key, route string) (*domain.IdempotencyRecord, error) {
+	ctx, done := r.start(ctx, "get")
+	record, err := r.next.Get(ctx, key, route)
+	done(err)
+	return record, err
+}
+
+func (r *idempotencyRepository) Complete(ctx context.Context, key, route string, response domain.IdempotentResponse) error {
+	ctx, done := r.start(ctx, "complete")
+	err := r.next.Complete(ctx, key, route, response)
+	done(err)
+	return err
+}
+
+func (r *idempotencyRepository) Delete(ctx context.Context, key, route string) error {
+	ctx, done := r.start(ctx, "delete")
+	err := r.next.Delete(ctx, key, route)
+	done(err)
+	return err
+}
+
+func (r *idempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int, error) {
+	ctx, done := r.start(ctx, "delete_expired")
+	deleted, err := r.next.DeleteExpired(ctx, now)
+	done(err)
+	return deleted, err
+}
@@ -0,0 +1,43 @@
+// Package instrumented оборачивает репозитории: каждая операция хранилища получает
+// спан OpenTelemetry и замер длительности в метриках
+package instrumented
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/guverz/pr-reviewer-service/internal/metrics"
+	"github.com/guverz/pr-reviewer-service/internal/repository"
+)
+
+var tracer = otel.Tracer("github.com/guverz/pr-reviewer-service/internal/repository")
+
+// startOperation открывает спан операции repoName.operation. Возвращаемая функция
+// закрывает спан, отмечая ошибку, и записывает длительность операции в метрики.
+func startOperation(ctx context.Context, m *metrics.Metrics, repoName, operation string) (context.Context, func(error)) {
+	start := time.Now()
+	ctx, span := tracer.Start(ctx, repoName+"."+operation, trace.WithAttributes(
+		attribute.String("storage.repository", repoName),
+		attribute.String("storage.operation", operation),
+	))
+
+	return ctx, func(err error) {
+		m.ObserveStorage(repoName, operation, start, err)
+		switch {
+		case errors.Is(err, repository.ErrNotFound), errors.Is(err, repository.ErrAlreadyExists),
+			errors.Is(err, repository.ErrVersionConflict):
+			// Ожидаемый исход, а не сбой хранилища: отмечаем в спане, но не как ошибку
+			span.SetAttributes(attribute.String("storage.result", err.Error()))
+		case err != nil:
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
@@ -0,0 +1,71 @@
+package instrumented
+
+import (
+	"context"
+
+	"github.com/guverz/pr-reviewer-service/internal/domain"
+	"github.com/guverz/pr-reviewer-service/internal/metrics"
+	"github.com/guverz/pr-reviewer-service/internal/repository"
+)
+
+type pullRequestRepository struct {
+	next    repository.PullRequestRepository
+	metrics *metrics.Metrics
+}
+
+func NewPullRequestRepository(next repository.PullRequestRepository, m *metrics.Metrics) repository.PullRequestRepository {
+	return &pullRequestRepository{next: next, metrics: m}
+}
+
+func (r *pullRequestRepository) start(ctx context.Context, operation string) (context.Context, func(error)) {
+	return startOperation(ctx, r.metrics, "pull_request", operation)
+}
+
+func (r *pullRequestRepository) Create(ctx context.Context, pr domain.PullRequest) error {
+	ctx, done := r.start(ctx, "create")
+	err := r.next.Create(ctx, pr)
+	done(err)
+	return err
+}
+
+func (r *pullRequestRepository) GetByID(ctx context.Context, prID string) (*domain.PullRequest, error) {
+	ctx, done := r.start(ctx, "get_by_id")
+	pr, err := r.next.GetByID(ctx, prID)
+	done(err)
+	return pr, err
+}
+
+func (r *pullRequestRepository) Update(ctx context.Context, pr domain.PullRequest) error {
+	ctx, done := r.start(ctx, "update")
+	err := r.next.Update(ctx, pr)
+	done(err)
+	return err
+}
+
+func (r *pullRequestRepository) UpdateMany(ctx context.Context, prs []domain.PullRequest) error {
+	ctx, done := r.start(ctx, "update_many")
+	err := r.next.UpdateMany(ctx, prs)
+	done(err)
+	return err
+}
+
+func (r *pullRequestRepository) ListByReviewer(ctx context.Context, reviewerID string) ([]domain.PullRequest, error) {
+	ctx, done := r.start(ctx, "list_by_reviewer")
+	prs, err := r.next.ListByReviewer(ctx, reviewerID)
+	done(err)
+	return prs, err
+}
+
+func (r *pullRequestRepository) CountOpenReviews(ctx context.Context, reviewerIDs []string) (map[string]int, error) {
+	ctx, done := r.start(ctx, "count_open_reviews")
+	counts, err := r.next.CountOpenReviews(ctx, reviewerIDs)
+	done(err)
+	return counts, err
+}
+
+func (r *pullRequestRepository) List(ctx context.Context, filter domain.PullRequestFilter) (*domain.PullRequestPage, error) {
+	ctx, done := r.start(ctx, "list")
+	page, err := r.next.List(ctx, filter)
+	done(err)
+	return page, err
+}
@@ -0,0 +1,64 @@
+package instrumented
+
+import (
+	"context"
+
+	"github.com/guverz/pr-reviewer-service/internal/domain"
+	"github.com/guverz/pr-reviewer-service/internal/metrics"
+	"github.com/guverz/pr-reviewer-service/internal/repository"
+)
+
+type roleRepository struct {
+	next    repository.RoleRepository
+	metrics *metrics.Metrics
+}
+
+func NewRoleRepository(next repository.RoleRepository, m *metrics.Metrics) repository.RoleRepository {
+	return &roleRepository{next: next, metrics: m}
+}
+
+func (r *roleRepository) start(ctx context.Context, operation string) (context.Context, func(error)) {
+	return startOperation(ctx, r.metrics, "role", operation)
+}
+
+func (r *roleRepository) Assign(ctx context.Context, assignment domain.RoleAssignment) error {
+	ctx, done := r.start(ctx, "assign")
+	err := r.next.Assign(ctx, assignment)
+	done(err)
+	return err
+}
+
+func (r *roleRepository) Revoke(ctx context.Context, assignment domain.RoleAssignment) error {
+	ctx, done := r.start(ctx, "revoke")
+	err := r.next.Revoke(ctx, assignment)
+	done(err)
+	return err
+}
+
+func (r *roleRepository) ListByUser(ctx context.Context, userID string) ([]domain.RoleAssignment, error) {
+	ctx, done := r.start(ctx, "list_by_user")
+	assignments, err := r.next.ListByUser(ctx, userID)
+	done(err)
+	return assignments, err
+}
+
+func (r *roleRepository) List(ctx context.Context) ([]domain.RoleAssignment, error) {
+	ctx, done := r.start(ctx, "list")
+	assignments, err := r.next.List(ctx)
+	done(err)
+	return assignments, err
+}
+
+func (r *roleRepository) RenameTeam(ctx context.Context, oldName, newName string) error {
+	ctx, done := r.start(ctx, "rename_team")
+	err := r.next.RenameTeam(ctx, oldName, newName)
+	done(err)
+	return err
+}
+
+func (r *roleRepository) DeleteByTeam(ctx context.Context, teamName string) error {
+	ctx, done := r.start(ctx, "delete_by_team")
+	err := r.next.DeleteByTeam(ctx, teamName)
+	done(err)
+	return err
+}
@@ -0,0 +1,50 @@
+package instrumented
+
+import (
+	"context"
+
+	"github.com/guverz/pr-reviewer-service/internal/domain"
+	"github.com/guverz/pr-reviewer-service/internal/metrics"
+	"github.com/guverz/pr-reviewer-service/internal/repository"
+)
+
+type rotationRepository struct {
+	next    repository.RotationRepository
+	metrics *metrics.Metrics
+}
+
+func NewRotationRepository(next repository.RotationRepository, m *metrics.Metrics) repository.RotationRepository {
+	return &rotationRepository{next: next, metrics: m}
+}
+
+func (r *rotationRepository) start(ctx context.Context, operation string) (context.Context, func(error)) {
+	return startOperation(ctx, r.metrics, "rotation", operation)
+}
+
+func (r *rotationRepository) Get(ctx context.Context, teamName string) (*domain.ReviewerRotation, error) {
+	ctx, done := r.start(ctx, "get")
+	rotation, err := r.next.Get(ctx, teamName)
+	done(err)
+	return rotation, err
+}
+
+func (r *rotationRepository) Save(ctx context.Context, rotation domain.ReviewerRotation) error {
+	ctx, done := r.start(ctx, "save")
+	err := r.next.Save(ctx, rotation)
+	done(err)
+	return err
+}
+
+func (r *rotationRepository) RenameTeam(ctx context.Context, oldName, newName string) error {
+	ctx, done := r.start(ctx, "rename_team")
+	err := r.next.RenameTeam(ctx, oldName, newName)
+	done(err)
+	return err
+}
+
+func (r *rotationRepository) DeleteByTeam(ctx context.Context, teamName string) error {
+	ctx, done := r.start(ctx, "delete_by_team")
+	err := r.next.DeleteByTeam(ctx, teamName)
+	done(err)
+	return err
+}
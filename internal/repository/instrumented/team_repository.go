@@ -0,0 +1,78 @@
+package instrumented
+
+import (
+	"context"
+
+	"github.com/guverz/pr-reviewer-service/internal/domain"
+	"github.com/guverz/pr-reviewer-service/internal/metrics"
+	"github.com/guverz/pr-reviewer-service/internal/repository"
+)
+
+type teamRepository struct {
+	next    repository.TeamRepository
+	metrics *metrics.Metrics
+}
+
+func NewTeamRepository(next repository.TeamRepository, m *metrics.Metrics) repository.TeamRepository {
+	return &teamRepository{next: next, metrics: m}
+}
+
+func (r *teamRepository) start(ctx context.Context, operation string) (context.Context, func(error)) {
+	return startOperation(ctx, r.metrics, "team", operation)
+}
+
+func (r *teamRepository) Create(ctx context.Context, team domain.Team) error {
+	ctx, done := r.start(ctx, "create")
+	err := r.next.Create(ctx, team)
+	done(err)
+	return err
+}
+
+func (r *teamRepository) GetByName(ctx context.Context, teamName string) (*domain.Team, error) {
+	ctx, done := r.start(ctx, "get_by_name")
+	team, err := r.next.GetByName(ctx, teamName)
+	done(err)
+	return team, err
+}
+
+func (r *teamRepository) UpdateMember(ctx context.Context, teamName string, userID string, isActive bool) error {
+	ctx, done := r.start(ctx, "update_member")
+	err := r.next.UpdateMember(ctx, teamName, userID, isActive)
+	done(err)
+	return err
+}
+
+func (r *teamRepository) SetMembers(ctx context.Context, teamName string, members []domain.TeamMember) error {
+	ctx, done := r.start(ctx, "set_members")
+	err := r.next.SetMembers(ctx, teamName, members)
+	done(err)
+	return err
+}
+
+func (r *teamRepository) RemoveMember(ctx context.Context, teamName string, userID string) error {
+	ctx, done := r.start(ctx, "remove_member")
+	err := r.next.RemoveMember(ctx, teamName, userID)
+	done(err)
+	return err
+}
+
+func (r *teamRepository) List(ctx context.Context) ([]domain.Team, error) {
+	ctx, done := r.start(ctx, "list")
+	teams, err := r.next.List(ctx)
+	done(err)
+	return teams, err
+}
+
+func (r *teamRepository) Rename(ctx context.Context, oldName, newName string) error {
+	ctx, done := r.start(ctx, "rename")
+	err := r.next.Rename(ctx, oldName, newName)
+	done(err)
+	return err
+}
+
+func (r *teamRepository) Delete(ctx context.Context, teamName string) error {
+	ctx, done := r.start(ctx, "delete")
+	err := r.next.Delete(ctx, teamName)
+	done(err)
+	return err
+}
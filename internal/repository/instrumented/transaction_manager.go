@@ -0,0 +1,26 @@
+package instrumented
+
+import (
+	"context"
+
+	"github.com/guverz/pr-reviewer-service/internal/metrics"
+	"github.com/guverz/pr-reviewer-service/internal/repository"
+)
+
+type transactionManager struct {
+	next    repository.TransactionManager
+	metrics *metrics.Metrics
+}
+
+func NewTransactionManager(next repository.TransactionManager, m *metrics.Metrics) repository.TransactionManager {
+	return &transactionManager{next: next, metrics: m}
+}
+
+// WithinTransaction замеряет транзакцию целиком, включая работу fn;
+// спаны операций внутри fn становятся дочерними для спана транзакции
+func (t *transactionManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	ctx, done := startOperation(ctx, t.metrics, "transaction", "within_transaction")
+	err := t.next.WithinTransaction(ctx, fn)
+	done(err)
+	return err
+}
@@ -0,0 +1,92 @@
+package instrumented
+
+import (
+	"context"
+
+	"github.com/guverz/pr-reviewer-service/internal/domain"
+	"github.com/guverz/pr-reviewer-service/internal/metrics"
+	"github.com/guverz/pr-reviewer-service/internal/repository"
+)
+
+type userRepository struct {
+	next    repository.UserRepository
+	metrics *metrics.Metrics
+}
+
+func NewUserRepository(next repository.UserRepository, m *metrics.Metrics) repository.UserRepository {
+	return &userRepository{next: next, metrics: m}
+}
+
+func (r *userRepository) start(ctx context.Context, operation string) (context.Context, func(error)) {
+	return startOperation(ctx, r.metrics, "user", operation)
+}
+
+func (r *userRepository) UpsertTeamMembers(ctx context.Context, teamName string, members []domain.TeamMember) error {
+	ctx, done := r.start(ctx, "upsert_team_members")
+	err := r.next.UpsertTeamMembers(ctx, teamName, members)
+	done(err)
+	return err
+}
+
+func (r *userRepository) GetByID(ctx context.Context, userID string) (*domain.User, error) {
+	ctx, done := r.start(ctx, "get_by_id")
+	user, err := r.next.GetByID(ctx, userID)
+	done(err)
+	return user, err
+}
+
+func (r *userRepository) GetByIDs(ctx context.Context, userIDs []string) (map[string]domain.User, error) {
+	ctx, done := r.start(ctx, "get_by_ids")
+	users, err := r.next.GetByIDs(ctx, userIDs)
+	done(err)
+	return users, err
+}
+
+func (r *userRepository) SetActive(ctx context.Context, userID string, isActive bool) (*domain.User, error) {
+	ctx, done := r.start(ctx, "set_active")
+	user, err := r.next.SetActive(ctx, userID, isActive)
+	done(err)
+	return user, err
+}
+
+func (r *userRepository) SetMaxOpenReviews(ctx context.Context, userID string, limit int) (*domain.User, error) {
+	ctx, done := r.start(ctx, "set_max_open_reviews")
+	user, err := r.next.SetMaxOpenReviews(ctx, userID, limit)
+	done(err)
+	return user, err
+}
+
+func (r *userRepository) SetSkills(ctx context.Context, userID string, skills []string) (*domain.User, error) {
+	ctx, done := r.start(ctx, "set_skills")
+	user, err := r.next.SetSkills(ctx, userID, skills)
+	done(err)
+	return user, err
+}
+
+func (r *userRepository) ListByTeam(ctx context.Context, teamName string, onlyActive bool) ([]domain.User, error) {
+	ctx, done := r.start(ctx, "list_by_team")
+	users, err := r.next.ListByTeam(ctx, teamName, onlyActive)
+	done(err)
+	return users, err
+}
+
+func (r *userRepository) List(ctx context.Context) ([]domain.User, error) {
+	ctx, done := r.start(ctx, "list")
+	users, err := r.next.List(ctx)
+	done(err)
+	return users, err
+}
+
+func (r *userRepository) RenameTeam(ctx context.Context, oldName, newName string) error {
+	ctx, done := r.start(ctx, "rename_team")
+	err := r.next.RenameTeam(ctx, oldName, newName)
+	done(err)
+	return err
+}
+
+func (r *userRepository) RemoveFromTeam(ctx context.Context, teamName string, userIDs []string) error {
+	ctx, done := r.start(ctx, "remove_from_team")
+	err := r.next.RemoveFromTeam(ctx, teamName, userIDs)
+	done(err)
+	return err
+}
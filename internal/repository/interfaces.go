@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/guverz/pr-reviewer-service/internal/domain"
 )
@@ -10,20 +11,98 @@ type TeamRepository interface {
 	Create(ctx context.Context, team domain.Team) error
 	GetByName(ctx context.Context, teamName string) (*domain.Team, error)
 	UpdateMember(ctx context.Context, teamName string, userID string, isActive bool) error
+	// SetMembers полностью заменяет состав команды
+	SetMembers(ctx context.Context, teamName string, members []domain.TeamMember) error
+	RemoveMember(ctx context.Context, teamName string, userID string) error
+	List(ctx context.Context) ([]domain.Team, error)
+	Rename(ctx context.Context, oldName, newName string) error
+	Delete(ctx context.Context, teamName string) error
 }
 
 type UserRepository interface {
+	// UpsertTeamMembers создаёт или обновляет пользователей; MaxOpenReviews и Skills существующих пользователей сохраняются
 	UpsertTeamMembers(ctx context.Context, teamName string, members []domain.TeamMember) error
 	GetByID(ctx context.Context, userID string) (*domain.User, error)
+	// GetByIDs возвращает найденных пользователей по ID; отсутствующие ID пропускаются
+	GetByIDs(ctx context.Context, userIDs []string) (map[string]domain.User, error)
 	SetActive(ctx context.Context, userID string, isActive bool) (*domain.User, error)
+	SetMaxOpenReviews(ctx context.Context, userID string, limit int) (*domain.User, error)
+	SetSkills(ctx context.Context, userID string, skills []string) (*domain.User, error)
 	ListByTeam(ctx context.Context, teamName string, onlyActive bool) ([]domain.User, error)
+	// List возвращает всех пользователей, отсортированных по ID
+	List(ctx context.Context) ([]domain.User, error)
+	// RenameTeam переносит всех пользователей команды oldName в команду newName
+	RenameTeam(ctx context.Context, oldName, newName string) error
+	// RemoveFromTeam снимает пользователей userIDs с команды teamName, не удаляя их:
+	// TeamName становится пустым. Пользователи других команд не меняются.
+	RemoveFromTeam(ctx context.Context, teamName string, userIDs []string) error
 }
 
 type PullRequestRepository interface {
+	// Create сохраняет PR с версией 1
 	Create(ctx context.Context, pr domain.PullRequest) error
 	GetByID(ctx context.Context, prID string) (*domain.PullRequest, error)
+	// Update сохраняет pr, только если в хранилище всё ещё версия pr.Version, и увеличивает её.
+	// Иначе возвращает ErrVersionConflict.
 	Update(ctx context.Context, pr domain.PullRequest) error
+	// UpdateMany атомарно сохраняет несколько PR по тем же правилам, что и Update:
+	// если версия хотя бы одного устарела, не сохраняется ни один
+	UpdateMany(ctx context.Context, prs []domain.PullRequest) error
 	ListByReviewer(ctx context.Context, reviewerID string) ([]domain.PullRequest, error)
+	// CountOpenReviews возвращает число открытых PR, где назначен каждый из reviewerIDs
+	// (пользователи без открытых ревью в результат не попадают)
+	CountOpenReviews(ctx context.Context, reviewerIDs []string) (map[string]int, error)
+	// List возвращает страницу PR, удовлетворяющих фильтру, в порядке filter.SortBy/filter.Order
+	// (при равенстве значений — по ID). Страница начинается строго после filter.After.
+	// filter.Limit <= 0 означает выборку без ограничения.
+	List(ctx context.Context, filter domain.PullRequestFilter) (*domain.PullRequestPage, error)
+}
+
+type RoleRepository interface {
+	Assign(ctx context.Context, assignment domain.RoleAssignment) error
+	Revoke(ctx context.Context, assignment domain.RoleAssignment) error
+	ListByUser(ctx context.Context, userID string) ([]domain.RoleAssignment, error)
+	List(ctx context.Context) ([]domain.RoleAssignment, error)
+	RenameTeam(ctx context.Context, oldName, newName string) error
+	DeleteByTeam(ctx context.Context, teamName string) error
+}
+
+// AvailabilityRepository хранит интервалы недоступности пользователей
+type AvailabilityRepository interface {
+	// Create возвращает ErrAlreadyExists, если интервал с таким ID уже есть
+	Create(ctx context.Context, window domain.Unavailability) error
+	GetByID(ctx context.Context, id string) (*domain.Unavailability, error)
+	Delete(ctx context.Context, id string) error
+	// ListByUser возвращает интервалы пользователя по возрастанию StartsAt
+	ListByUser(ctx context.Context, userID string) ([]domain.Unavailability, error)
+	// ListActive возвращает интервалы, в которые попадает момент at
+	ListActive(ctx context.Context, at time.Time) ([]domain.Unavailability, error)
+	// MarkReviewsReassigned отмечает, что открытые ревью пользователя сняты в момент at
+	MarkReviewsReassigned(ctx context.Context, id string, at time.Time) error
+}
+
+// RotationRepository хранит стратегию выбора ревьюеров и курсор round-robin по командам
+type RotationRepository interface {
+	// Get возвращает ErrNotFound, если ротация команды ещё не сохранялась
+	Get(ctx context.Context, teamName string) (*domain.ReviewerRotation, error)
+	// Save сохраняет ротацию, только если в хранилище всё ещё версия rotation.Version
+	// (0 — записи нет), и увеличивает её. Иначе возвращает ErrVersionConflict.
+	Save(ctx context.Context, rotation domain.ReviewerRotation) error
+	RenameTeam(ctx context.Context, oldName, newName string) error
+	DeleteByTeam(ctx context.Context, teamName string) error
+}
+
+// IdempotencyRepository хранит ответы на запросы с Idempotency-Key.
+// Истёкшие записи (ExpiresAt в прошлом) считаются отсутствующими.
+type IdempotencyRepository interface {
+	// Reserve сохраняет запись без ответа; ErrAlreadyExists, если для Key и Route есть неистёкшая запись
+	Reserve(ctx context.Context, record domain.IdempotencyRecord) error
+	Get(ctx context.Context, key, route string) (*domain.IdempotencyRecord, error)
+	// Complete сохраняет ответ в зарезервированную запись
+	Complete(ctx context.Context, key, route string, response domain.IdempotentResponse) error
+	Delete(ctx context.Context, key, route string) error
+	// DeleteExpired удаляет записи, истёкшие к моменту now, и возвращает их количество
+	DeleteExpired(ctx context.Context, now time.Time) (int, error)
 }
 
 type TransactionManager interface {
@@ -0,0 +1,542 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/guverz/pr-reviewer-service/internal/domain"
+	"github.com/guverz/pr-reviewer-service/internal/repository"
+)
+
+// AdminService выгружает и загружает полное состояние сервиса: команды, пользователей,
+// роли, интервалы недоступности, ротации ревьюеров и PR
+type AdminService struct {
+	teamRepo         repository.TeamRepository
+	userRepo         repository.UserRepository
+	prRepo           repository.PullRequestRepository
+	roleRepo         repository.RoleRepository
+	availabilityRepo repository.AvailabilityRepository
+	rotationRepo     repository.RotationRepository
+	txMgr            repository.TransactionManager
+	authorizer       *Authorizer
+}
+
+func NewAdminService(
+	teamRepo repository.TeamRepository,
+	userRepo repository.UserRepository,
+	prRepo repository.PullRequestRepository,
+	roleRepo repository.RoleRepository,
+	availabilityRepo repository.AvailabilityRepository,
+	rotationRepo repository.RotationRepository,
+	txMgr repository.TransactionManager,
+	authorizer *Authorizer,
+) *AdminService {
+	return &AdminService{
+		teamRepo:         teamRepo,
+		userRepo:         userRepo,
+		prRepo:           prRepo,
+		roleRepo:         roleRepo,
+		availabilityRepo: availabilityRepo,
+		rotationRepo:     rotationRepo,
+		txMgr:            txMgr,
+		authorizer:       authorizer,
+	}
+}
+
+// Export передаёт в emit все команды, затем пользователей, роли, интервалы недоступности,
+// ротации ревьюеров и PR — в порядке, в котором Import проверяет ссылки между ними.
+// Снимок собирается в одной транзакции, а в emit передаётся уже после неё,
+// чтобы медленный клиент не задерживал запись.
+func (s *AdminService) Export(ctx context.Context, emit func(domain.ImportRecord) error) error {
+	ctx, span := tracer.Start(ctx, "AdminService.Export")
+	defer span.End()
+
+	if err := s.authorizer.Authorize(ctx, domain.PermissionManageTeams, ""); err != nil {
+		return err
+	}
+
+	var records []domain.ImportRecord
+	err := s.txMgr.WithinTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		records, err = s.snapshot(txCtx)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, rec := range records {
+		if err := emit(rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// snapshot собирает записи выгрузки внутри открытой транзакции
+func (s *AdminService) snapshot(ctx context.Context) ([]domain.ImportRecord, error) {
+	var records []domain.ImportRecord
+
+	teams, err := s.teamRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range teams {
+		records = append(records, domain.ImportRecord{Team: &teams[i]})
+	}
+
+	users, err := s.userRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range users {
+		records = append(records, domain.ImportRecord{User: &users[i]})
+	}
+
+	roles, err := s.roleRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range roles {
+		records = append(records, domain.ImportRecord{Role: &roles[i]})
+	}
+
+	for _, user := range users {
+		windows, err := s.availabilityRepo.ListByUser(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		for i := range windows {
+			records = append(records, domain.ImportRecord{Unavailability: &windows[i]})
+		}
+	}
+
+	// Ротация есть только у команд, для которых стратегию меняли или назначали по очереди
+	for _, team := range teams {
+		rotation, err := s.rotationRepo.Get(ctx, team.Name)
+		if errors.Is(err, repository.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, domain.ImportRecord{Rotation: rotation})
+	}
+
+	page, err := s.prRepo.List(ctx, domain.PullRequestFilter{
+		SortBy: domain.PullRequestSortByID,
+		Order:  domain.SortOrderAsc,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range page.Items {
+		records = append(records, domain.ImportRecord{PullRequest: &page.Items[i]})
+	}
+
+	return records, nil
+}
+
+// Import проверяет записи и, если ошибок нет и это не dry-run, применяет их.
+// Проверки и запись выполняются в одной транзакции, поэтому параллельные изменения
+// не вклиниваются между ними. Команды, PR и интервалы недоступности, которые уже существуют,
+// а также пользователи и ротации, сохранённые с другими данными, пропускаются и попадают
+// в отчёт как конфликты. Уже выданные роли пропускаются молча.
+// Ссылки на отсутствующих пользователей и команды считаются ошибками, и тогда импорт не применяется.
+func (s *AdminService) Import(ctx context.Context, records []domain.ImportRecord, dryRun bool) (*domain.ImportReport, error) {
+	ctx, span := tracer.Start(ctx, "AdminService.Import")
+	defer span.End()
+
+	if err := s.authorizer.Authorize(ctx, domain.PermissionManageTeams, ""); err != nil {
+		return nil, err
+	}
+
+	var report *domain.ImportReport
+	err := s.txMgr.WithinTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		report, err = s.importRecords(txCtx, records, dryRun)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// importRecords выполняет Import внутри открытой транзакции
+func (s *AdminService) importRecords(ctx context.Context, records []domain.ImportRecord, dryRun bool) (*domain.ImportReport, error) {
+	report := &domain.ImportReport{
+		DryRun:     dryRun,
+		Conflicts:  []domain.ImportConflict{},
+		Violations: []domain.ImportViolation{},
+	}
+
+	existingTeams, err := s.teamRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	teamExists := make(map[string]bool, len(existingTeams))
+	for _, team := range existingTeams {
+		teamExists[team.Name] = true
+	}
+
+	existingUsers, err := s.userRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	storedUsers := make(map[string]domain.User, len(existingUsers))
+	for _, user := range existingUsers {
+		storedUsers[user.ID] = user
+	}
+
+	existingRoles, err := s.roleRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	storedRoles := make(map[domain.RoleAssignment]bool, len(existingRoles))
+	for _, assignment := range existingRoles {
+		storedRoles[assignment] = true
+	}
+
+	violate := func(line int, format string, args ...any) {
+		report.Violations = append(report.Violations, domain.ImportViolation{
+			Line:    line,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+	conflict := func(line int, kind, id, reason string) {
+		report.Conflicts = append(report.Conflicts, domain.ImportConflict{
+			Line:   line,
+			Kind:   kind,
+			ID:     id,
+			Reason: reason,
+		})
+	}
+
+	// Первый проход: что объявлено в файле
+	fileTeams := make(map[string]bool)
+	fileUsers := make(map[string]bool)
+	filePRs := make(map[string]bool)
+	fileRoles := make(map[domain.RoleAssignment]bool)
+	fileWindows := make(map[string]bool)
+	fileRotations := make(map[string]bool)
+	for _, rec := range records {
+		switch {
+		case rec.Team != nil:
+			if fileTeams[rec.Team.Name] {
+				violate(rec.Line, "duplicate team %q", rec.Team.Name)
+			}
+			fileTeams[rec.Team.Name] = true
+		case rec.User != nil:
+			if fileUsers[rec.User.ID] {
+				violate(rec.Line, "duplicate user %q", rec.User.ID)
+			}
+			fileUsers[rec.User.ID] = true
+		case rec.PullRequest != nil:
+			if filePRs[rec.PullRequest.ID] {
+				violate(rec.Line, "duplicate pull request %q", rec.PullRequest.ID)
+			}
+			filePRs[rec.PullRequest.ID] = true
+		case rec.Role != nil:
+			if fileRoles[*rec.Role] {
+				violate(rec.Line, "duplicate role %q of user %q", rec.Role.Role, rec.Role.UserID)
+			}
+			fileRoles[*rec.Role] = true
+		case rec.Unavailability != nil:
+			if fileWindows[rec.Unavailability.ID] {
+				violate(rec.Line, "duplicate unavailability %q", rec.Unavailability.ID)
+			}
+			fileWindows[rec.Unavailability.ID] = true
+		case rec.Rotation != nil:
+			if fileRotations[rec.Rotation.TeamName] {
+				violate(rec.Line, "duplicate reviewer rotation of team %q", rec.Rotation.TeamName)
+			}
+			fileRotations[rec.Rotation.TeamName] = true
+		}
+	}
+
+	userKnown := func(userID string) bool {
+		_, stored := storedUsers[userID]
+		return stored || fileUsers[userID]
+	}
+	teamKnown := func(teamName string) bool {
+		return teamExists[teamName] || fileTeams[teamName]
+	}
+
+	// Второй проход: ссылочная целостность и конфликты
+	var newTeams []domain.ImportRecord
+	newUsers := make(map[string][]domain.TeamMember)
+	// Лимиты ревью и теги экспертизы не входят в состав команды и записываются отдельно
+	reviewLimits := make(map[string]int)
+	userSkills := make(map[string][]string)
+	var newPRs []domain.ImportRecord
+	var newRoles []domain.RoleAssignment
+	var newWindows []domain.ImportRecord
+	var newRotations []domain.ImportRecord
+	for _, rec := range records {
+		switch {
+		case rec.Team != nil:
+			team := *rec.Team
+			if team.Name == "" {
+				violate(rec.Line, "team_name is required")
+				continue
+			}
+			for _, member := range team.Members {
+				if !userKnown(member.UserID) {
+					violate(rec.Line, "team %q references unknown user %q", team.Name, member.UserID)
+				}
+			}
+			if teamExists[team.Name] {
+				conflict(rec.Line, domain.ImportKindTeam, team.Name, "team already exists")
+				continue
+			}
+			newTeams = append(newTeams, rec)
+
+		case rec.User != nil:
+			user := *rec.User
+			if user.ID == "" {
+				violate(rec.Line, "user_id is required")
+				continue
+			}
+			if user.MaxOpenReviews < 0 {
+				violate(rec.Line, "user %q has negative max_open_reviews", user.ID)
+				continue
+			}
+			// Пустая команда — пользователь, снятый с команды при синхронизации или удалении
+			if user.TeamName != "" && !teamKnown(user.TeamName) {
+				violate(rec.Line, "user %q references unknown team %q", user.ID, user.TeamName)
+				continue
+			}
+			user.Skills = domain.NormalizeSkills(user.Skills)
+			if stored, ok := storedUsers[user.ID]; ok {
+				if !stored.Equal(user) {
+					conflict(rec.Line, domain.ImportKindUser, user.ID, "user already exists with different data")
+				}
+				continue
+			}
+			newUsers[user.TeamName] = append(newUsers[user.TeamName], domain.TeamMember{
+				UserID:   user.ID,
+				Username: user.Username,
+				IsActive: user.IsActive,
+			})
+			if user.MaxOpenReviews > 0 {
+				reviewLimits[user.ID] = user.MaxOpenReviews
+			}
+			if len(user.Skills) > 0 {
+				userSkills[user.ID] = user.Skills
+			}
+
+		case rec.PullRequest != nil:
+			pr := *rec.PullRequest
+			if pr.ID == "" {
+				violate(rec.Line, "pull_request_id is required")
+				continue
+			}
+			if !pr.Status.IsValid() {
+				violate(rec.Line, "pull request %q has invalid status %q", pr.ID, pr.Status)
+			}
+			if !userKnown(pr.AuthorID) {
+				violate(rec.Line, "pull request %q references unknown author %q", pr.ID, pr.AuthorID)
+			}
+			for _, reviewerID := range pr.AssignedReviewers {
+				if !userKnown(reviewerID) {
+					violate(rec.Line, "pull request %q references unknown reviewer %q", pr.ID, reviewerID)
+				}
+			}
+			_, err := s.prRepo.GetByID(ctx, pr.ID)
+			if err == nil {
+				conflict(rec.Line, domain.ImportKindPullRequest, pr.ID, "pull request already exists")
+				continue
+			}
+			if !errors.Is(err, repository.ErrNotFound) {
+				return nil, err
+			}
+			newPRs = append(newPRs, rec)
+
+		case rec.Role != nil:
+			assignment := *rec.Role
+			if !assignment.Role.IsValid() {
+				violate(rec.Line, "unknown role %q", assignment.Role)
+				continue
+			}
+			if !userKnown(assignment.UserID) {
+				violate(rec.Line, "role %q references unknown user %q", assignment.Role, assignment.UserID)
+				continue
+			}
+			if assignment.Role.IsTeamScoped() {
+				if !teamKnown(assignment.TeamName) {
+					violate(rec.Line, "role %q references unknown team %q", assignment.Role, assignment.TeamName)
+					continue
+				}
+			} else {
+				assignment.TeamName = ""
+			}
+			if storedRoles[assignment] {
+				continue
+			}
+			newRoles = append(newRoles, assignment)
+
+		case rec.Unavailability != nil:
+			window := *rec.Unavailability
+			if window.ID == "" {
+				violate(rec.Line, "unavailability id is required")
+				continue
+			}
+			if !window.Reason.IsValid() {
+				violate(rec.Line, "unavailability %q has invalid reason %q", window.ID, window.Reason)
+			}
+			if !window.EndsAt.After(window.StartsAt) {
+				violate(rec.Line, "unavailability %q must end after it starts", window.ID)
+			}
+			if !userKnown(window.UserID) {
+				violate(rec.Line, "unavailability %q references unknown user %q", window.ID, window.UserID)
+			}
+			_, err := s.availabilityRepo.GetByID(ctx, window.ID)
+			if err == nil {
+				conflict(rec.Line, domain.ImportKindUnavailability, window.ID, "unavailability already exists")
+				continue
+			}
+			if !errors.Is(err, repository.ErrNotFound) {
+				return nil, err
+			}
+			newWindows = append(newWindows, rec)
+
+		case rec.Rotation != nil:
+			rotation := *rec.Rotation
+			if !rotation.Strategy.IsValid() {
+				violate(rec.Line, "reviewer rotation of team %q has invalid strategy %q", rotation.TeamName, rotation.Strategy)
+			}
+			if !teamKnown(rotation.TeamName) {
+				violate(rec.Line, "reviewer rotation references unknown team %q", rotation.TeamName)
+			}
+			if rotation.LastUserID != "" && !userKnown(rotation.LastUserID) {
+				violate(rec.Line, "reviewer rotation of team %q references unknown user %q", rotation.TeamName, rotation.LastUserID)
+			}
+			stored, err := s.rotationRepo.Get(ctx, rotation.TeamName)
+			if err == nil {
+				if stored.Strategy != rotation.Strategy || stored.LastUserID != rotation.LastUserID {
+					conflict(rec.Line, domain.ImportKindRotation, rotation.TeamName, "reviewer rotation already exists with different data")
+				}
+				continue
+			}
+			if !errors.Is(err, repository.ErrNotFound) {
+				return nil, err
+			}
+			newRotations = append(newRotations, rec)
+		}
+	}
+
+	report.TeamsImported = len(newTeams)
+	for _, members := range newUsers {
+		report.UsersImported += len(members)
+	}
+	report.PullRequestsImported = len(newPRs)
+	report.RolesImported = len(newRoles)
+	report.UnavailabilityImported = len(newWindows)
+	report.RotationsImported = len(newRotations)
+
+	if len(report.Violations) > 0 || dryRun {
+		return report, nil
+	}
+
+	teamNames := make([]string, 0, len(newUsers))
+	for teamName := range newUsers {
+		teamNames = append(teamNames, teamName)
+	}
+	slices.Sort(teamNames)
+
+	// Проверки выше сделаны в той же транзакции; если хранилище всё же сообщит о дубликате,
+	// запись пропускается как конфликт, а не обрывает частично применённый импорт
+	for _, rec := range newTeams {
+		err := s.teamRepo.Create(ctx, *rec.Team)
+		switch {
+		case errors.Is(err, repository.ErrAlreadyExists):
+			conflict(rec.Line, domain.ImportKindTeam, rec.Team.Name, "team already exists")
+			report.TeamsImported--
+			teamExists[rec.Team.Name] = true
+		case err != nil:
+			return nil, err
+		}
+	}
+	for _, teamName := range teamNames {
+		if err := s.userRepo.UpsertTeamMembers(ctx, teamName, newUsers[teamName]); err != nil {
+			return nil, err
+		}
+		// Состав новой команды задаёт её запись в файле, а в существующую команду
+		// новые пользователи дописываются, как при синхронизации команды
+		if teamExists[teamName] {
+			if err := s.addTeamMembers(ctx, teamName, newUsers[teamName]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for userID, limit := range reviewLimits {
+		if _, err := s.userRepo.SetMaxOpenReviews(ctx, userID, limit); err != nil {
+			return nil, err
+		}
+	}
+	for userID, skills := range userSkills {
+		if _, err := s.userRepo.SetSkills(ctx, userID, skills); err != nil {
+			return nil, err
+		}
+	}
+	for _, assignment := range newRoles {
+		if err := s.roleRepo.Assign(ctx, assignment); err != nil {
+			return nil, err
+		}
+	}
+	for _, rec := range newWindows {
+		err := s.availabilityRepo.Create(ctx, *rec.Unavailability)
+		switch {
+		case errors.Is(err, repository.ErrAlreadyExists):
+			conflict(rec.Line, domain.ImportKindUnavailability, rec.Unavailability.ID, "unavailability already exists")
+			report.UnavailabilityImported--
+		case err != nil:
+			return nil, err
+		}
+	}
+	for _, rec := range newRotations {
+		// Версия в хранилище своя: ротация создаётся заново с версии 0
+		rotation := *rec.Rotation
+		rotation.Version = 0
+		err := s.rotationRepo.Save(ctx, rotation)
+		switch {
+		case errors.Is(err, repository.ErrVersionConflict):
+			conflict(rec.Line, domain.ImportKindRotation, rotation.TeamName, "reviewer rotation already exists")
+			report.RotationsImported--
+		case err != nil:
+			return nil, err
+		}
+	}
+	for _, rec := range newPRs {
+		err := s.prRepo.Create(ctx, *rec.PullRequest)
+		switch {
+		case errors.Is(err, repository.ErrAlreadyExists):
+			conflict(rec.Line, domain.ImportKindPullRequest, rec.PullRequest.ID, "pull request already exists")
+			report.PullRequestsImported--
+		case err != nil:
+			return nil, err
+		}
+	}
+
+	report.Applied = true
+	return report, nil
+}
+
+// addTeamMembers дописывает в состав команды участников, которых в нём ещё нет
+func (s *AdminService) addTeamMembers(ctx context.Context, teamName string, members []domain.TeamMember) error {
+	team, err := s.teamRepo.GetByName(ctx, teamName)
+	if err != nil {
+		return err
+	}
+
+	roster := team.Members
+	for _, member := range members {
+		if !slices.ContainsFunc(roster, func(m domain.TeamMember) bool { return m.UserID == member.UserID }) {
+			roster = append(roster, member)
+		}
+	}
+	return s.teamRepo.SetMembers(ctx, teamName, roster)
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/guverz/pr-reviewer-service/internal/domain"
+)
+
+func TestImportAddsUsersToExistingTeam(t *testing.T) {
+	ts := newTestServices(t)
+	ctx := context.Background()
+	ts.createTeam(t, "backend", "u1")
+
+	records := []domain.ImportRecord{
+		{Line: 1, Team: &domain.Team{Name: "backend"}},
+		{Line: 2, User: &domain.User{ID: "u2", Username: "u2", TeamName: "backend", IsActive: true}},
+	}
+	report, err := ts.admin.Import(ctx, records, false)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if !report.Applied || report.UsersImported != 1 || len(report.Conflicts) != 1 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+
+	team, err := ts.teams.GetTeam(ctx, "backend")
+	if err != nil {
+		t.Fatalf("get team: %v", err)
+	}
+	if len(team.Members) != 2 || team.Members[1].UserID != "u2" {
+		t.Fatalf("imported user is not in the roster: %+v", team.Members)
+	}
+
+	// Участник есть в составе команды, поэтому его можно деактивировать
+	if _, err := ts.users.SetActive(ctx, "u2", false); err != nil {
+		t.Fatalf("set active: %v", err)
+	}
+}
+
+func TestConcurrentImportsDoNotFail(t *testing.T) {
+	ts := newTestServices(t)
+	records := []domain.ImportRecord{
+		{Line: 1, Team: &domain.Team{Name: "backend", Members: []domain.TeamMember{{UserID: "u1", Username: "u1", IsActive: true}}}},
+		{Line: 2, User: &domain.User{ID: "u1", Username: "u1", TeamName: "backend", IsActive: true}},
+		{Line: 3, PullRequest: &domain.PullRequest{ID: "pr-1", Name: "pr", AuthorID: "u1", Status: domain.PullRequestStatusOpen, AssignedReviewers: []string{}}},
+	}
+
+	const imports = 10
+	reports := make([]*domain.ImportReport, imports)
+	errs := make([]error, imports)
+	var wg sync.WaitGroup
+	for i := 0; i < imports; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			reports[i], errs[i] = ts.admin.Import(context.Background(), records, false)
+		}(i)
+	}
+	wg.Wait()
+
+	created := 0
+	for i := range reports {
+		if errs[i] != nil {
+			t.Fatalf("import %d: %v", i, errs[i])
+		}
+		if reports[i].TeamsImported == 1 && reports[i].PullRequestsImported == 1 {
+			created++
+		} else if len(reports[i].Conflicts) != 2 {
+			t.Errorf("import %d: expected team and PR conflicts, got %+v", i, reports[i])
+		}
+	}
+	if created != 1 {
+		t.Fatalf("expected exactly one import to create the data, got %d", created)
+	}
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	src := newTestServices(t)
+	src.createTeam(t, "backend", "u1", "u2")
+
+	startsAt := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	window := domain.Unavailability{
+		ID:        "un-1",
+		UserID:    "u2",
+		Reason:    domain.UnavailabilityReasonVacation,
+		StartsAt:  startsAt,
+		EndsAt:    startsAt.Add(72 * time.Hour),
+		CreatedAt: startsAt.Add(-time.Hour),
+	}
+	if err := src.repos.Availability.Create(ctx, window); err != nil {
+		t.Fatalf("create unavailability: %v", err)
+	}
+	lead := domain.RoleAssignment{UserID: "u1", Role: domain.RoleTeamLead, TeamName: "backend"}
+	if err := src.repos.Role.Assign(ctx, lead); err != nil {
+		t.Fatalf("assign role: %v", err)
+	}
+	rotation := domain.ReviewerRotation{TeamName: "backend", Strategy: domain.ReviewerStrategyRoundRobin, LastUserID: "u1"}
+	if err := src.repos.Rotation.Save(ctx, rotation); err != nil {
+		t.Fatalf("save rotation: %v", err)
+	}
+
+	var records []domain.ImportRecord
+	err := src.admin.Export(ctx, func(rec domain.ImportRecord) error {
+		rec.Line = len(records) + 1
+		records = append(records, rec)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	dst := newTestServices(t)
+	report, err := dst.admin.Import(ctx, records, false)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if !report.Applied || report.RolesImported != 1 || report.UnavailabilityImported != 1 || report.RotationsImported != 1 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+
+	roles, _ := dst.repos.Role.ListByUser(ctx, "u1")
+	if !reflect.DeepEqual(roles, []domain.RoleAssignment{lead}) {
+		t.Errorf("roles = %+v", roles)
+	}
+	imported, err := dst.repos.Availability.GetByID(ctx, "un-1")
+	if err != nil || !reflect.DeepEqual(*imported, window) {
+		t.Errorf("unavailability = %+v, %v", imported, err)
+	}
+	stored, err := dst.repos.Rotation.Get(ctx, "backend")
+	if err != nil || stored.Strategy != rotation.Strategy || stored.LastUserID != rotation.LastUserID {
+		t.Errorf("rotation = %+v, %v", stored, err)
+	}
+
+	// Повторный импорт того же файла ничего не меняет: роль и ротация совпадают,
+	// а интервал уже есть и попадает в конфликты
+	report, err = dst.admin.Import(ctx, records, false)
+	if err != nil {
+		t.Fatalf("repeat import: %v", err)
+	}
+	if report.RolesImported != 0 || report.RotationsImported != 0 || report.UnavailabilityImported != 0 {
+		t.Fatalf("repeat import wrote data: %+v", report)
+	}
+}
+
+func TestImportRejectsDanglingReferences(t *testing.T) {
+	ts := newTestServices(t)
+	ts.createTeam(t, "backend", "u1")
+
+	startsAt := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	records := []domain.ImportRecord{
+		{Line: 1, Role: &domain.RoleAssignment{UserID: "u1", Role: domain.RoleTeamLead, TeamName: "frontend"}},
+		{Line: 2, Unavailability: &domain.Unavailability{ID: "un-1", UserID: "ghost", Reason: domain.UnavailabilityReasonOther, StartsAt: startsAt, EndsAt: startsAt.Add(time.Hour)}},
+		{Line: 3, Rotation: &domain.ReviewerRotation{TeamName: "backend", Strategy: "fifo"}},
+	}
+	report, err := ts.admin.Import(context.Background(), records, false)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if report.Applied {
+		t.Fatal("import with violations must not be applied")
+	}
+	lines := make([]int, len(report.Violations))
+	for i, v := range report.Violations {
+		lines[i] = v.Line
+	}
+	if !reflect.DeepEqual(lines, []int{1, 2, 3}) {
+		t.Fatalf("violations = %+v", report.Violations)
+	}
+}
+
+func TestExportImportRoundTripAfterSyncAndDelete(t *testing.T) {
+	ctx := context.Background()
+	src := newTestServices(t)
+	src.createTeam(t, "backend", "u1", "u2", "u3")
+	src.createTeam(t, "qa", "q1", "q2")
+	for prID, authorID := range map[string]string{"pr-1": "u1", "pr-2": "q1"} {
+		if _, err := src.prs.CreatePR(ctx, prID, prID, authorID, nil); err != nil {
+			t.Fatalf("create %s: %v", prID, err)
+		}
+		if _, err := src.prs.MergePR(ctx, prID, 0); err != nil {
+			t.Fatalf("merge %s: %v", prID, err)
+		}
+	}
+	// u2 и u3 снимаются с команды, а q1 и q2 остаются без команды после её удаления
+	if _, err := src.syncMembers("backend", "u1"); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if _, err := src.teams.DeleteTeam(ctx, "qa", ""); err != nil {
+		t.Fatalf("delete team: %v", err)
+	}
+
+	var records []domain.ImportRecord
+	err := src.admin.Export(ctx, func(rec domain.ImportRecord) error {
+		rec.Line = len(records) + 1
+		records = append(records, rec)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	dst := newTestServices(t)
+	report, err := dst.admin.Import(ctx, records, false)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if !report.Applied || len(report.Violations) != 0 || report.UsersImported != 5 || report.PullRequestsImported != 2 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+
+	for _, userID := range []string{"u2", "u3", "q1", "q2"} {
+		user, err := dst.repos.User.GetByID(ctx, userID)
+		if err != nil || user.TeamName != "" {
+			t.Errorf("user %s = %+v, %v; want a user without team", userID, user, err)
+		}
+	}
+	team, err := dst.teams.GetTeam(ctx, "backend")
+	if err != nil || len(team.Members) != 1 || team.Members[0].UserID != "u1" {
+		t.Fatalf("backend = %+v, %v; want only u1", team, err)
+	}
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+
+	"github.com/guverz/pr-reviewer-service/internal/auth"
+	"github.com/guverz/pr-reviewer-service/internal/domain"
+	"github.com/guverz/pr-reviewer-service/internal/repository"
+)
+
+// Authorizer проверяет права вызывающего из контекста запроса.
+// Контекст без Identity считается системным вызовом (фоновые задачи, импорт) и разрешается.
+type Authorizer struct {
+	roleRepo repository.RoleRepository
+}
+
+func NewAuthorizer(roleRepo repository.RoleRepository) *Authorizer {
+	return &Authorizer{
+		roleRepo: roleRepo,
+	}
+}
+
+// Authorize проверяет право permission в команде teamName.
+// Пустой teamName означает действие вне команд — его разрешают только глобальные роли.
+func (a *Authorizer) Authorize(ctx context.Context, permission domain.Permission, teamName string) error {
+	identity, ok := auth.FromContext(ctx)
+	if !ok || identity.IsAdmin() {
+		return nil
+	}
+
+	if identity.UserID != "" {
+		assignments, err := a.roleRepo.ListByUser(ctx, identity.UserID)
+		if err != nil {
+			return err
+		}
+
+		for _, assignment := range assignments {
+			if !assignment.Role.Grants(permission) {
+				continue
+			}
+			if !assignment.Role.IsTeamScoped() || (teamName != "" && assignment.TeamName == teamName) {
+				return nil
+			}
+		}
+	}
+
+	return domainError(ctx, domain.ErrorCodeForbidden, "permission %s denied", permission).
+		WithDetail("permission", permission)
+}
+
+// IsCaller сообщает, выполняется ли запрос от имени пользователя userID
+func (a *Authorizer) IsCaller(ctx context.Context, userID string) bool {
+	identity, ok := auth.FromContext(ctx)
+	return ok && identity.UserID != "" && identity.UserID == userID
+}
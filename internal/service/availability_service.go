@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/guverz/pr-reviewer-service/internal/domain"
+	"github.com/guverz/pr-reviewer-service/internal/repository"
+)
+
+// AvailabilityService управляет интервалами недоступности (отпуск, дежурство, неполные дни).
+// Пока интервал идёт, ReviewerSelector не выбирает пользователя ревьюером.
+type AvailabilityService struct {
+	availabilityRepo repository.AvailabilityRepository
+	userRepo         repository.UserRepository
+	prRepo           repository.PullRequestRepository
+	prService        *PullRequestService
+	authorizer       *Authorizer
+}
+
+func NewAvailabilityService(
+	availabilityRepo repository.AvailabilityRepository,
+	userRepo repository.UserRepository,
+	prRepo repository.PullRequestRepository,
+	prService *PullRequestService,
+	authorizer *Authorizer,
+) *AvailabilityService {
+	return &AvailabilityService{
+		availabilityRepo: availabilityRepo,
+		userRepo:         userRepo,
+		prRepo:           prRepo,
+		prService:        prService,
+		authorizer:       authorizer,
+	}
+}
+
+// AddUnavailability добавляет пользователю интервал недоступности.
+// Добавить интервал может сам пользователь, лид его команды или администратор.
+func (s *AvailabilityService) AddUnavailability(ctx context.Context, window domain.Unavailability) (*domain.Unavailability, error) {
+	ctx, span := tracer.Start(ctx, "AvailabilityService.AddUnavailability")
+	defer span.End()
+
+	if err := s.authorizeUser(ctx, window.UserID); err != nil {
+		return nil, err
+	}
+
+	if !window.Reason.IsValid() {
+		return nil, domainError(ctx, domain.ErrorCodeValidation, "unknown unavailability reason %q", window.Reason)
+	}
+	if !window.EndsAt.After(window.StartsAt) {
+		return nil, domainError(ctx, domain.ErrorCodeValidation, "ends_at must be after starts_at")
+	}
+
+	window.ID = newUnavailabilityID()
+	window.ReviewsReassignedAt = nil
+	window.CreatedAt = time.Now()
+	if err := s.availabilityRepo.Create(ctx, window); err != nil {
+		return nil, err
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("user_id", window.UserID).
+		Str("unavailability_id", window.ID).
+		Str("reason", string(window.Reason)).
+		Time("starts_at", window.StartsAt).
+		Time("ends_at", window.EndsAt).
+		Msg("unavailability added")
+
+	return &window, nil
+}
+
+// ListUnavailability возвращает интервалы недоступности пользователя.
+// Как и менять их, смотреть интервалы может сам пользователь, лид его команды или администратор.
+func (s *AvailabilityService) ListUnavailability(ctx context.Context, userID string) ([]domain.Unavailability, error) {
+	ctx, span := tracer.Start(ctx, "AvailabilityService.ListUnavailability")
+	defer span.End()
+
+	if err := s.authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return s.availabilityRepo.ListByUser(ctx, userID)
+}
+
+// DeleteUnavailability удаляет интервал недоступности пользователя
+func (s *AvailabilityService) DeleteUnavailability(ctx context.Context, userID, id string) error {
+	ctx, span := tracer.Start(ctx, "AvailabilityService.DeleteUnavailability")
+	defer span.End()
+
+	if err := s.authorizeUser(ctx, userID); err != nil {
+		return err
+	}
+
+	window, err := s.availabilityRepo.GetByID(ctx, id)
+	if err != nil {
+		return lookupError(ctx, err, "unavailability not found", "unavailability_id", id)
+	}
+	if window.UserID != userID {
+		return domainError(ctx, domain.ErrorCodeNotFound, "unavailability not found").
+			WithDetail("unavailability_id", id)
+	}
+
+	if err := s.availabilityRepo.Delete(ctx, id); err != nil {
+		return lookupError(ctx, err, "unavailability not found", "unavailability_id", id)
+	}
+	return nil
+}
+
+// ReassignUnavailableReviews снимает открытые ревью с пользователей, у которых начался интервал
+// с ReassignReviews. Интервал обрабатывается один раз; если какой-то PR не удалось обновить,
+// интервал остаётся необработанным и будет повторён при следующем запуске.
+// Возвращает число PR, с которых снят ревьюер.
+func (s *AvailabilityService) ReassignUnavailableReviews(ctx context.Context) (int, error) {
+	ctx, span := tracer.Start(ctx, "AvailabilityService.ReassignUnavailableReviews")
+	defer span.End()
+
+	now := time.Now()
+	windows, err := s.availabilityRepo.ListActive(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	released := 0
+	var errs []error
+	for _, window := range windows {
+		if !window.ReassignReviews || window.ReviewsReassignedAt != nil {
+			continue
+		}
+
+		prs, err := s.prRepo.ListByReviewer(ctx, window.UserID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("list reviews of %s: %w", window.UserID, err))
+			continue
+		}
+
+		failed := false
+		for _, pr := range prs {
+			if pr.IsMerged() {
+				continue
+			}
+			ok, err := s.prService.releaseReviewer(ctx, pr.ID, window.UserID)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("release reviewer %s from %s: %w", window.UserID, pr.ID, err))
+				failed = true
+				continue
+			}
+			if ok {
+				released++
+			}
+		}
+		if failed {
+			continue
+		}
+
+		if err := s.availabilityRepo.MarkReviewsReassigned(ctx, window.ID, now); err != nil && !errors.Is(err, repository.ErrNotFound) {
+			errs = append(errs, fmt.Errorf("mark unavailability %s: %w", window.ID, err))
+		}
+	}
+
+	return released, errors.Join(errs...)
+}
+
+// authorizeUser пропускает самого пользователя userID, лида его команды и администратора
+func (s *AvailabilityService) authorizeUser(ctx context.Context, userID string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return lookupError(ctx, err, "user not found", "user_id", userID)
+	}
+	if s.authorizer.IsCaller(ctx, userID) {
+		return nil
+	}
+	return s.authorizer.Authorize(ctx, domain.PermissionManageMembers, user.TeamName)
+}
+
+func newUnavailabilityID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("ua-%x", time.Now().UnixNano())
+	}
+	return "ua-" + hex.EncodeToString(b)
+}
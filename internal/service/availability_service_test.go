@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/guverz/pr-reviewer-service/internal/auth"
+	"github.com/guverz/pr-reviewer-service/internal/domain"
+)
+
+func TestListUnavailabilityAuthorization(t *testing.T) {
+	ts := newTestServices(t)
+	ts.createTeam(t, "backend", "u1", "u2", "lead")
+	if err := ts.repos.Role.Assign(context.Background(), domain.RoleAssignment{UserID: "lead", Role: domain.RoleTeamLead, TeamName: "backend"}); err != nil {
+		t.Fatalf("assign role: %v", err)
+	}
+	availability := NewAvailabilityService(ts.repos.Availability, ts.repos.User, ts.repos.PullRequest, ts.prs, NewAuthorizer(ts.repos.Role))
+
+	tests := []struct {
+		name      string
+		callerID  string
+		forbidden bool
+	}{
+		{name: "self", callerID: "u1"},
+		{name: "team lead", callerID: "lead"},
+		{name: "other user", callerID: "u2", forbidden: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := auth.WithIdentity(context.Background(), auth.Identity{UserID: tt.callerID, Scope: auth.ScopeUser})
+			_, err := availability.ListUnavailability(ctx, "u1")
+			if forbidden := errors.Is(err, domain.ErrorCodeForbidden); forbidden != tt.forbidden || (!tt.forbidden && err != nil) {
+				t.Fatalf("list as %s: %v", tt.callerID, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/guverz/pr-reviewer-service/internal/domain"
+	"github.com/guverz/pr-reviewer-service/internal/repository"
+)
+
+// IdempotencyService запоминает ответы на запросы с Idempotency-Key, чтобы повтор
+// запроса (например, ретрай CI по таймауту) не выполнял изменение второй раз
+type IdempotencyService struct {
+	repo repository.IdempotencyRepository
+	ttl  time.Duration
+}
+
+func NewIdempotencyService(repo repository.IdempotencyRepository, ttl time.Duration) *IdempotencyService {
+	return &IdempotencyService{
+		repo: repo,
+		ttl:  ttl,
+	}
+}
+
+// Begin резервирует ключ для запроса. Возвращает nil, если запрос нужно выполнить,
+// или сохранённую запись, ответ из которой нужно повторить. Тот же ключ с другим запросом —
+// IDEMPOTENCY_KEY_REUSED; ключ, запрос по которому ещё выполняется, — IDEMPOTENCY_KEY_IN_USE.
+func (s *IdempotencyService) Begin(ctx context.Context, key, route, requestHash string) (*domain.IdempotencyRecord, error) {
+	ctx, span := tracer.Start(ctx, "IdempotencyService.Begin")
+	defer span.End()
+
+	// Запись могла истечь между Reserve и Get, тогда пробуем зарезервировать ключ ещё раз
+	for {
+		now := time.Now()
+		err := s.repo.Reserve(ctx, domain.IdempotencyRecord{
+			Key:         key,
+			Route:       route,
+			RequestHash: requestHash,
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(s.ttl),
+		})
+		if err == nil {
+			return nil, nil
+		}
+		if !errors.Is(err, repository.ErrAlreadyExists) {
+			return nil, err
+		}
+
+		existing, err := s.repo.Get(ctx, key, route)
+		if errors.Is(err, repository.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case existing.RequestHash != requestHash:
+			return nil, domainError(ctx, domain.ErrorCodeIdempotencyKeyReused,
+				"idempotency key was already used with a different request").
+				WithDetail("idempotency_key", key)
+		case existing.Response == nil:
+			return nil, domainError(ctx, domain.ErrorCodeIdempotencyKeyInUse,
+				"request with this idempotency key is still in progress").
+				WithDetail("idempotency_key", key)
+		}
+		return existing, nil
+	}
+}
+
+// Complete сохраняет ответ на запрос, начатый Begin
+func (s *IdempotencyService) Complete(ctx context.Context, key, route string, response domain.IdempotentResponse) error {
+	ctx, span := tracer.Start(ctx, "IdempotencyService.Complete")
+	defer span.End()
+
+	return s.repo.Complete(ctx, key, route, response)
+}
+
+// Abort освобождает ключ, если запрос завершился сбоем, чтобы повтор выполнил его заново
+func (s *IdempotencyService) Abort(ctx context.Context, key, route string) error {
+	ctx, span := tracer.Start(ctx, "IdempotencyService.Abort")
+	defer span.End()
+
+	return s.repo.Delete(ctx, key, route)
+}
+
+// DeleteExpired удаляет истёкшие ключи и возвращает их количество
+func (s *IdempotencyService) DeleteExpired(ctx context.Context) (int, error) {
+	ctx, span := tracer.Start(ctx, "IdempotencyService.DeleteExpired")
+	defer span.End()
+
+	return s.repo.DeleteExpired(ctx, time.Now())
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/guverz/pr-reviewer-service/internal/domain"
+	"github.com/guverz/pr-reviewer-service/internal/repository"
+)
+
+// domainError создаёт доменную ошибку, пишет её в логгер запроса и отмечает в текущем спане.
+// Возвращает DomainError, чтобы вызывающий мог добавить детали через WithDetail.
+func domainError(ctx context.Context, code domain.ErrorCode, format string, args ...any) domain.DomainError {
+	err := domain.DomainError{Code: code, Message: fmt.Sprintf(format, args...)}
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.String("error.code", string(code)))
+	span.SetStatus(codes.Error, err.Error())
+	zerolog.Ctx(ctx).Info().
+		Str("code", string(code)).
+		Str("error", err.Error()).
+		Msg("domain error")
+	return err
+}
+
+// lookupError превращает repository.ErrNotFound в доменную ошибку NOT_FOUND с деталью key=value.
+// Остальные ошибки — сбои хранилища — возвращаются как есть и станут ответом 500.
+func lookupError(ctx context.Context, err error, message, key string, value any) error {
+	if errors.Is(err, repository.ErrNotFound) {
+		return domainError(ctx, domain.ErrorCodeNotFound, "%s", message).WithDetail(key, value)
+	}
+	return err
+}
@@ -2,169 +2,491 @@ package service
 
 import (
 	"context"
+	"errors"
+	"slices"
 	"time"
 
+	"github.com/rs/zerolog"
+
+	"github.com/guverz/pr-reviewer-service/internal/auth"
 	"github.com/guverz/pr-reviewer-service/internal/domain"
+	"github.com/guverz/pr-reviewer-service/internal/metrics"
 	"github.com/guverz/pr-reviewer-service/internal/repository"
 )
 
 type PullRequestService struct {
 	prRepo           repository.PullRequestRepository
 	userRepo         repository.UserRepository
+	txMgr            repository.TransactionManager
 	reviewerSelector *ReviewerSelector
+	authorizer       *Authorizer
+	metrics          *metrics.Metrics
 }
 
 func NewPullRequestService(
 	prRepo repository.PullRequestRepository,
 	userRepo repository.UserRepository,
+	txMgr repository.TransactionManager,
 	reviewerSelector *ReviewerSelector,
+	authorizer *Authorizer,
+	metrics *metrics.Metrics,
 ) *PullRequestService {
 	return &PullRequestService{
 		prRepo:           prRepo,
 		userRepo:         userRepo,
+		txMgr:            txMgr,
 		reviewerSelector: reviewerSelector,
+		authorizer:       authorizer,
+		metrics:          metrics,
 	}
 }
 
-// CreatePR создаёт PR и автоматически назначает до 2 ревьюеров из команды автора
-func (s *PullRequestService) CreatePR(ctx context.Context, prID, prName, authorID string) (*domain.PullRequest, error) {
-	// Проверяем, существует ли PR
-	existing, err := s.prRepo.GetByID(ctx, prID)
-	if err == nil && existing != nil {
-		return nil, domain.NewDomainError(domain.ErrorCodePRExists, "PR id already exists")
-	}
+// CreatePR создаёт PR и автоматически назначает до 2 ревьюеров из команды автора;
+// если заданы requiredSkills, предпочтение отдаётся участникам с этими тегами экспертизы.
+// Проверка существования, выбор ревьюеров и запись выполняются в одной транзакции,
+// поэтому из параллельных запросов с одним ID успешен ровно один, остальные получают PR_EXISTS.
+// Если очередь round-robin сдвинул параллельный запрос, транзакция повторяется с новым выбором.
+func (s *PullRequestService) CreatePR(ctx context.Context, prID, prName, authorID string, requiredSkills []string) (*domain.PullRequest, error) {
+	ctx, span := tracer.Start(ctx, "PullRequestService.CreatePR")
+	defer span.End()
+
+	requiredSkills = domain.NormalizeSkills(requiredSkills)
+	var (
+		pr          domain.PullRequest
+		author      *domain.User
+		teamMembers []domain.User
+	)
+	createPR := func(txCtx context.Context) error {
+		// Проверяем, существует ли PR
+		_, err := s.prRepo.GetByID(txCtx, prID)
+		switch {
+		case err == nil:
+			return prExistsError(ctx, prID)
+		case !errors.Is(err, repository.ErrNotFound):
+			return err
+		}
 
-	// Получаем автора
-	author, err := s.userRepo.GetByID(ctx, authorID)
-	if err != nil {
-		return nil, domain.NewDomainError(domain.ErrorCodeNotFound, "author not found")
-	}
+		// Получаем автора
+		author, err = s.userRepo.GetByID(txCtx, authorID)
+		if err != nil {
+			return lookupError(ctx, err, "author not found", "user_id", authorID)
+		}
 
-	// Получаем активных участников команды автора
-	teamMembers, err := s.userRepo.ListByTeam(ctx, author.TeamName, true)
-	if err != nil {
-		return nil, domain.NewDomainError(domain.ErrorCodeNotFound, "team not found")
-	}
+		// Получаем активных участников команды автора
+		teamMembers, err = s.userRepo.ListByTeam(txCtx, author.TeamName, true)
+		if err != nil {
+			return lookupError(ctx, err, "team not found", "team_name", author.TeamName)
+		}
 
-	// Выбираем ревьюеров
-	reviewers := s.reviewerSelector.SelectReviewers(teamMembers, authorID, 2)
+		// Выбираем ревьюеров
+		selection, err := s.reviewerSelector.SelectReviewers(txCtx, author.TeamName, nil, teamMembers, authorID, requiredSkills, 2)
+		if err != nil {
+			return err
+		}
 
-	// Создаём PR
-	pr := domain.PullRequest{
-		ID:                prID,
-		Name:              prName,
-		AuthorID:          authorID,
-		Status:            domain.PullRequestStatusOpen,
-		AssignedReviewers: reviewers,
-		NeedMoreReviewers: len(reviewers) < 2,
-		CreatedAt:         time.Now(),
-		MergedAt:          nil,
+		// Создаём PR; если ревьюеров не хватило, причина сохраняется в PR, а не перегружает кого-то
+		pr = domain.PullRequest{
+			ID:                prID,
+			Name:              prName,
+			AuthorID:          authorID,
+			Status:            domain.PullRequestStatusOpen,
+			AssignedReviewers: selection.Reviewers,
+			CreatedAt:         time.Now(),
+			MergedAt:          nil,
+		}
+		if len(requiredSkills) > 0 {
+			pr.RequiredSkills = requiredSkills
+			pr.MatchedSkills = selection.MatchedSkills
+		}
+		pr.UpdateNeedMoreReviewers(selection.Shortage)
+
+		// Хранилище без сериализуемых транзакций сообщит о гонке через ErrAlreadyExists
+		if err := s.prRepo.Create(txCtx, pr); err != nil {
+			if errors.Is(err, repository.ErrAlreadyExists) {
+				return prExistsError(ctx, prID)
+			}
+			return err
+		}
+		// Очередь сдвигается только вместе с созданным PR
+		return s.reviewerSelector.SaveRotation(txCtx, selection.Rotation)
 	}
 
-	if err := s.prRepo.Create(ctx, pr); err != nil {
+	var err error
+	for attempt := 1; attempt <= maxUpdateAttempts; attempt++ {
+		err = s.txMgr.WithinTransaction(ctx, createPR)
+		if !errors.Is(err, repository.ErrVersionConflict) {
+			break
+		}
+		zerolog.Ctx(ctx).Debug().
+			Str("pull_request_id", prID).
+			Int("attempt", attempt).
+			Msg("reviewer rotation conflict, retrying")
+	}
+	if err != nil {
 		return nil, err
 	}
+	s.metrics.PullRequestCreated()
+
+	zerolog.Ctx(ctx).Info().
+		Str("pull_request_id", pr.ID).
+		Str("author_id", authorID).
+		Str("team_name", author.TeamName).
+		Int("candidates", len(teamMembers)).
+		Strs("reviewers", pr.AssignedReviewers).
+		Bool("need_more_reviewers", pr.NeedMoreReviewers).
+		Str("reviewer_shortage", string(pr.ReviewerShortage)).
+		Strs("required_skills", pr.RequiredSkills).
+		Int("skill_matched_reviewers", len(pr.MatchedSkills)).
+		Msg("reviewers assigned")
 
 	return &pr, nil
 }
 
-// MergePR помечает PR как MERGED (идемпотентная операция)
-func (s *PullRequestService) MergePR(ctx context.Context, prID string) (*domain.PullRequest, error) {
-	pr, err := s.prRepo.GetByID(ctx, prID)
+// MergePR помечает PR как MERGED (идемпотентная операция).
+// expectedVersion > 0 — версия PR, которую видел клиент (If-Match); 0 — без проверки.
+func (s *PullRequestService) MergePR(ctx context.Context, prID string, expectedVersion int64) (*domain.PullRequest, error) {
+	ctx, span := tracer.Start(ctx, "PullRequestService.MergePR")
+	defer span.End()
+
+	merged := false
+	pr, err := s.updatePR(ctx, prID, expectedVersion, nil, func(_ context.Context, pr *domain.PullRequest) (bool, error) {
+		// Если уже merged, просто возвращаем текущее состояние (идемпотентность)
+		merged = !pr.IsMerged()
+		if !merged {
+			return false, nil
+		}
+
+		// Помечаем как merged
+		now := time.Now()
+		pr.Status = domain.PullRequestStatusMerged
+		pr.MergedAt = &now
+		pr.MergedBy = auth.ActorFromContext(ctx)
+		return true, nil
+	})
 	if err != nil {
-		return nil, domain.NewDomainError(domain.ErrorCodeNotFound, "PR not found")
+		return nil, err
 	}
-
-	// Если уже merged, просто возвращаем текущее состояние (идемпотентность)
-	if pr.IsMerged() {
+	if !merged {
 		return pr, nil
 	}
+	s.metrics.PullRequestMerged()
 
-	// Помечаем как merged
-	now := time.Now()
-	pr.Status = domain.PullRequestStatusMerged
-	pr.MergedAt = &now
-
-	if err := s.prRepo.Update(ctx, *pr); err != nil {
-		return nil, err
-	}
+	zerolog.Ctx(ctx).Info().
+		Str("pull_request_id", pr.ID).
+		Str("merged_by", pr.MergedBy).
+		Msg("pull request merged")
 
 	return pr, nil
 }
 
-// ReassignReviewer переназначает ревьюера на другого из его команды
-func (s *PullRequestService) ReassignReviewer(ctx context.Context, prID, oldReviewerID string) (*domain.PullRequest, string, error) {
-	// Получаем PR
-	pr, err := s.prRepo.GetByID(ctx, prID)
-	if err != nil {
-		return nil, "", domain.NewDomainError(domain.ErrorCodeNotFound, "PR not found")
+// ReassignReviewer переназначает ревьюера на другого из его команды.
+// expectedVersion > 0 — версия PR, которую видел клиент (If-Match); 0 — без проверки.
+func (s *PullRequestService) ReassignReviewer(ctx context.Context, prID, oldReviewerID string, expectedVersion int64) (*domain.PullRequest, string, error) {
+	ctx, span := tracer.Start(ctx, "PullRequestService.ReassignReviewer")
+	defer span.End()
+
+	var (
+		newReviewerID string
+		candidates    []domain.User
+		rotation      *domain.ReviewerRotation
+	)
+	saveRotation := func(txCtx context.Context) error {
+		return s.reviewerSelector.SaveRotation(txCtx, rotation)
 	}
+	pr, err := s.updatePR(ctx, prID, expectedVersion, saveRotation, func(txCtx context.Context, pr *domain.PullRequest) (bool, error) {
+		// Проверяем, что PR не merged
+		if pr.IsMerged() {
+			return false, domainError(ctx, domain.ErrorCodePRMerged, "cannot reassign on merged PR").
+				WithDetail("pull_request_id", prID)
+		}
 
-	// Проверяем, что PR не merged
-	if pr.IsMerged() {
-		return nil, "", domain.NewDomainError(domain.ErrorCodePRMerged, "cannot reassign on merged PR")
-	}
+		// Проверяем, что старый ревьюер назначен
+		if !pr.HasReviewer(oldReviewerID) {
+			return false, domainError(ctx, domain.ErrorCodeNotAssigned, "reviewer is not assigned to this PR").
+				WithDetail("pull_request_id", prID).
+				WithDetail("user_id", oldReviewerID)
+		}
 
-	// Проверяем, что старый ревьюер назначен
-	if !pr.HasReviewer(oldReviewerID) {
-		return nil, "", domain.NewDomainError(domain.ErrorCodeNotAssigned, "reviewer is not assigned to this PR")
-	}
+		// Ревьюер может передать своё ревью сам, остальным нужно право в команде автора PR
+		if !s.authorizer.IsCaller(ctx, oldReviewerID) {
+			authorTeam := ""
+			author, err := s.userRepo.GetByID(txCtx, pr.AuthorID)
+			switch {
+			case err == nil:
+				authorTeam = author.TeamName
+			case !errors.Is(err, repository.ErrNotFound):
+				return false, err
+			}
+			if err := s.authorizer.Authorize(txCtx, domain.PermissionReassignReviewers, authorTeam); err != nil {
+				return false, err
+			}
+		}
+
+		// Получаем старого ревьюера для определения его команды
+		oldReviewer, err := s.userRepo.GetByID(txCtx, oldReviewerID)
+		if err != nil {
+			return false, lookupError(ctx, err, "reviewer not found", "user_id", oldReviewerID)
+		}
+
+		// Получаем активных участников команды старого ревьюера
+		teamMembers, err := s.userRepo.ListByTeam(txCtx, oldReviewer.TeamName, true)
+		if err != nil {
+			return false, lookupError(ctx, err, "team not found", "team_name", oldReviewer.TeamName)
+		}
 
-	// Получаем старого ревьюера для определения его команды
-	oldReviewer, err := s.userRepo.GetByID(ctx, oldReviewerID)
+		// Исключаем только автора и заменяемого ревьюера
+		// Уже назначенные ревьюеры могут быть выбраны снова (по ТЗ это допустимо)
+		excludeIDs := make(map[string]bool)
+		excludeIDs[oldReviewerID] = true
+		excludeIDs[pr.AuthorID] = true
+
+		// Фильтруем кандидатов (только активные, исключая автора и заменяемого)
+		candidates = make([]domain.User, 0)
+		for _, member := range teamMembers {
+			if member.IsActive && !excludeIDs[member.ID] {
+				candidates = append(candidates, member)
+			}
+		}
+
+		// Выбираем случайного кандидата; недоступные и те, у кого исчерпан лимит ревью, пропускаются.
+		// Ручное переназначение не снимает ревьюера без замены, а сообщает причину.
+		selection, err := s.reviewerSelector.SelectReviewers(txCtx, oldReviewer.TeamName, nil, candidates, "", pr.RequiredSkills, 1)
+		if err != nil {
+			return false, err
+		}
+		if len(selection.Reviewers) == 0 {
+			s.metrics.NoCandidate()
+			return false, domainError(ctx, domain.ErrorCodeNoCandidate, "no active replacement candidate in team").
+				WithDetail("pull_request_id", prID).
+				WithDetail("team_name", oldReviewer.TeamName).
+				WithDetail("reason", selection.Shortage)
+		}
+
+		newReviewerID = selection.Reviewers[0]
+		rotation = selection.Rotation
+
+		// Заменяем ревьюера
+		pr.ReplaceReviewer(oldReviewerID, newReviewerID)
+		pr.SetMatchedSkills(newReviewerID, selection.MatchedSkills[newReviewerID])
+		pr.UpdateNeedMoreReviewers("")
+		pr.Reassignments = append(pr.Reassignments, domain.ReviewerReassignment{
+			OldReviewerID: oldReviewerID,
+			NewReviewerID: newReviewerID,
+			ActorID:       auth.ActorFromContext(ctx),
+			At:            time.Now(),
+		})
+		return true, nil
+	})
 	if err != nil {
-		return nil, "", domain.NewDomainError(domain.ErrorCodeNotFound, "reviewer not found")
+		return nil, "", err
 	}
+	s.metrics.ReviewerReassigned(metrics.ReassignReasonManual)
 
-	// Получаем активных участников команды старого ревьюера
-	teamMembers, err := s.userRepo.ListByTeam(ctx, oldReviewer.TeamName, true)
-	if err != nil {
-		return nil, "", domain.NewDomainError(domain.ErrorCodeNotFound, "team not found")
+	zerolog.Ctx(ctx).Info().
+		Str("pull_request_id", pr.ID).
+		Str("old_reviewer_id", oldReviewerID).
+		Str("new_reviewer_id", newReviewerID).
+		Int("candidates", len(candidates)).
+		Msg("reviewer reassigned")
+
+	return pr, newReviewerID, nil
+}
+
+// releaseReviewer снимает недоступного ревьюера с открытого PR и назначает замену из его команды
+// (кроме автора и уже назначенных ревьюеров). Если замены нет, ревьюер снимается и PR помечается
+// как требующий ревьюеров. Возвращает false, если PR уже смержен или ревьюер с него снят.
+func (s *PullRequestService) releaseReviewer(ctx context.Context, prID, reviewerID string) (bool, error) {
+	var (
+		released      bool
+		newReviewerID string
+		rotation      *domain.ReviewerRotation
+	)
+	saveRotation := func(txCtx context.Context) error {
+		return s.reviewerSelector.SaveRotation(txCtx, rotation)
 	}
+	pr, err := s.updatePR(ctx, prID, 0, saveRotation, func(txCtx context.Context, pr *domain.PullRequest) (bool, error) {
+		released, newReviewerID, rotation = false, "", nil
+		if pr.IsMerged() || !pr.HasReviewer(reviewerID) {
+			return false, nil
+		}
 
-	// Исключаем только автора и заменяемого ревьюера
-	// Уже назначенные ревьюеры могут быть выбраны снова (по ТЗ это допустимо)
-	excludeIDs := make(map[string]bool)
-	excludeIDs[oldReviewerID] = true
-	excludeIDs[pr.AuthorID] = true
+		reviewer, err := s.userRepo.GetByID(txCtx, reviewerID)
+		if err != nil {
+			return false, lookupError(ctx, err, "reviewer not found", "user_id", reviewerID)
+		}
+		teamMembers, err := s.userRepo.ListByTeam(txCtx, reviewer.TeamName, true)
+		if err != nil {
+			return false, lookupError(ctx, err, "team not found", "team_name", reviewer.TeamName)
+		}
 
-	// Фильтруем кандидатов (только активные, исключая автора и заменяемого)
-	candidates := make([]domain.User, 0)
-	for _, member := range teamMembers {
-		if member.IsActive && !excludeIDs[member.ID] {
-			candidates = append(candidates, member)
+		candidates := make([]domain.User, 0, len(teamMembers))
+		for _, member := range teamMembers {
+			if member.ID != pr.AuthorID && !pr.HasReviewer(member.ID) {
+				candidates = append(candidates, member)
+			}
 		}
+		selection, err := s.reviewerSelector.SelectReviewers(txCtx, reviewer.TeamName, nil, candidates, "", pr.RequiredSkills, 1)
+		if err != nil {
+			return false, err
+		}
+		rotation = selection.Rotation
+
+		if len(selection.Reviewers) == 0 {
+			pr.RemoveReviewer(reviewerID)
+		} else {
+			newReviewerID = selection.Reviewers[0]
+			pr.ReplaceReviewer(reviewerID, newReviewerID)
+			pr.SetMatchedSkills(newReviewerID, selection.MatchedSkills[newReviewerID])
+		}
+		pr.UpdateNeedMoreReviewers(selection.Shortage)
+		pr.Reassignments = append(pr.Reassignments, domain.ReviewerReassignment{
+			OldReviewerID: reviewerID,
+			NewReviewerID: newReviewerID,
+			At:            time.Now(),
+		})
+		released = true
+		return true, nil
+	})
+	if err != nil || !released {
+		return false, err
 	}
 
-	if len(candidates) == 0 {
-		return nil, "", domain.NewDomainError(domain.ErrorCodeNoCandidate, "no active replacement candidate in team")
+	if newReviewerID == "" {
+		s.metrics.NoCandidate()
+		zerolog.Ctx(ctx).Warn().
+			Str("pull_request_id", prID).
+			Str("reviewer_id", reviewerID).
+			Str("reason", string(pr.ReviewerShortage)).
+			Msg("no replacement candidate, unavailable reviewer removed")
+		return true, nil
 	}
+	s.metrics.ReviewerReassigned(metrics.ReassignReasonUnavailable)
+	zerolog.Ctx(ctx).Info().
+		Str("pull_request_id", prID).
+		Str("old_reviewer_id", reviewerID).
+		Str("new_reviewer_id", newReviewerID).
+		Msg("unavailable reviewer reassigned")
+	return true, nil
+}
 
-	// Выбираем случайного кандидата
-	selected := s.reviewerSelector.SelectReviewers(candidates, "", 1)
-	if len(selected) == 0 {
-		return nil, "", domain.NewDomainError(domain.ErrorCodeNoCandidate, "no active replacement candidate in team")
+// maxUpdateAttempts — сколько раз PR перечитывается и изменяется заново при конфликте версий
+const maxUpdateAttempts = 3
+
+// updatePR читает PR, применяет к нему mutate и сохраняет с проверкой версии (compare-and-swap).
+// mutate возвращает false, если сохранять нечего. Чтение, mutate, запись и afterUpdate
+// (если задан) выполняются в одной транзакции; afterUpdate вызывается только после успешной
+// записи PR. При конфликте версий PR перечитывается и mutate вызывается заново.
+// Если задан expectedVersion, конфликт версии PR не повторяется: клиент видел устаревшую версию
+// и получает PRECONDITION_FAILED. Конфликт ротации ревьюеров из afterUpdate повторяется всегда.
+func (s *PullRequestService) updatePR(
+	ctx context.Context,
+	prID string,
+	expectedVersion int64,
+	afterUpdate func(ctx context.Context) error,
+	mutate func(ctx context.Context, pr *domain.PullRequest) (bool, error),
+) (*domain.PullRequest, error) {
+	for attempt := 1; ; attempt++ {
+		var pr *domain.PullRequest
+		err := s.txMgr.WithinTransaction(ctx, func(txCtx context.Context) error {
+			current, err := s.prRepo.GetByID(txCtx, prID)
+			if err != nil {
+				return lookupError(ctx, err, "PR not found", "pull_request_id", prID)
+			}
+			if expectedVersion > 0 && current.Version != expectedVersion {
+				return preconditionFailedError(ctx, prID, expectedVersion)
+			}
+
+			changed, err := mutate(txCtx, current)
+			if err != nil {
+				return err
+			}
+			if !changed {
+				pr = current
+				return nil
+			}
+
+			if err := s.prRepo.Update(txCtx, *current); err != nil {
+				return err
+			}
+			current.Version++
+			pr = current
+
+			if afterUpdate != nil {
+				return afterUpdate(txCtx)
+			}
+			return nil
+		})
+		switch {
+		case err == nil:
+			return pr, nil
+		case !errors.Is(err, repository.ErrVersionConflict):
+			return nil, err
+		case errors.Is(err, domain.ErrorCodeConcurrentUpdate):
+			// Конфликт ротации: PR клиент видел актуальным, выбор нужно повторить
+			if attempt == maxUpdateAttempts {
+				return nil, err
+			}
+		case expectedVersion > 0:
+			return nil, preconditionFailedError(ctx, prID, expectedVersion)
+		case attempt == maxUpdateAttempts:
+			return nil, domainError(ctx, domain.ErrorCodeConcurrentUpdate, "PR was modified concurrently, retry the request").
+				WithDetail("pull_request_id", prID)
+		}
+
+		zerolog.Ctx(ctx).Debug().
+			Str("pull_request_id", prID).
+			Int("attempt", attempt).
+			Msg("PR version conflict, retrying")
 	}
+}
 
-	newReviewerID := selected[0]
+// GetPR получает PR вместе с автором и ревьюерами
+func (s *PullRequestService) GetPR(ctx context.Context, prID string) (*domain.PullRequestDetails, error) {
+	ctx, span := tracer.Start(ctx, "PullRequestService.GetPR")
+	defer span.End()
 
-	// Заменяем ревьюера
-	pr.ReplaceReviewer(oldReviewerID, newReviewerID)
-	pr.NeedMoreReviewers = len(pr.AssignedReviewers) < 2
+	pr, err := s.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		return nil, lookupError(ctx, err, "PR not found", "pull_request_id", prID)
+	}
 
-	if err := s.prRepo.Update(ctx, *pr); err != nil {
-		return nil, "", err
+	// Автора и ревьюеров загружаем одним запросом
+	userIDs := make([]string, 0, len(pr.AssignedReviewers)+1)
+	userIDs = append(userIDs, pr.AuthorID)
+	userIDs = append(userIDs, pr.AssignedReviewers...)
+
+	users, err := s.userRepo.GetByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, err
 	}
 
-	return pr, newReviewerID, nil
+	details := &domain.PullRequestDetails{
+		PullRequest: *pr,
+		Reviewers:   make([]domain.User, 0, len(pr.AssignedReviewers)),
+	}
+	if author, ok := users[pr.AuthorID]; ok {
+		details.Author = &author
+	}
+	for _, reviewerID := range pr.AssignedReviewers {
+		reviewer, ok := users[reviewerID]
+		if !ok {
+			reviewer = domain.User{ID: reviewerID}
+		}
+		details.Reviewers = append(details.Reviewers, reviewer)
+	}
+
+	return details, nil
 }
 
 // GetPRsByReviewer получает список PR, где пользователь назначен ревьюером
 func (s *PullRequestService) GetPRsByReviewer(ctx context.Context, reviewerID string) ([]domain.PullRequest, error) {
+	ctx, span := tracer.Start(ctx, "PullRequestService.GetPRsByReviewer")
+	defer span.End()
+
 	// Проверяем, что пользователь существует
 	_, err := s.userRepo.GetByID(ctx, reviewerID)
 	if err != nil {
-		return nil, domain.NewDomainError(domain.ErrorCodeNotFound, "user not found")
+		return nil, lookupError(ctx, err, "user not found", "user_id", reviewerID)
 	}
 
 	prs, err := s.prRepo.ListByReviewer(ctx, reviewerID)
@@ -174,3 +496,64 @@ func (s *PullRequestService) GetPRsByReviewer(ctx context.Context, reviewerID st
 
 	return prs, nil
 }
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 200
+)
+
+// ListPRs возвращает страницу PR по фильтру.
+// Если указан teamName, выборка ограничивается PR авторов из этой команды.
+func (s *PullRequestService) ListPRs(ctx context.Context, filter domain.PullRequestFilter, teamName string) (*domain.PullRequestPage, error) {
+	ctx, span := tracer.Start(ctx, "PullRequestService.ListPRs")
+	defer span.End()
+
+	if filter.SortBy == "" {
+		filter.SortBy = domain.PullRequestSortByCreatedAt
+	}
+	if filter.Order == "" {
+		filter.Order = domain.SortOrderDesc
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultListLimit
+	}
+	if filter.Limit > maxListLimit {
+		filter.Limit = maxListLimit
+	}
+	if filter.After != nil && filter.After.SortBy != filter.SortBy {
+		return nil, domainError(ctx, domain.ErrorCodeValidation, "cursor does not match sort_by")
+	}
+	// Курсор по возрастанию, применённый к убывающей выборке, молча отдал бы не ту страницу
+	if filter.After != nil && filter.After.Order != filter.Order {
+		return nil, domainError(ctx, domain.ErrorCodeValidation, "cursor does not match order")
+	}
+
+	if teamName != "" {
+		members, err := s.userRepo.ListByTeam(ctx, teamName, false)
+		if err != nil {
+			return nil, err
+		}
+
+		teamAuthorIDs := make([]string, 0, len(members))
+		for _, member := range members {
+			if filter.AuthorIDs == nil || slices.Contains(filter.AuthorIDs, member.ID) {
+				teamAuthorIDs = append(teamAuthorIDs, member.ID)
+			}
+		}
+		if len(teamAuthorIDs) == 0 {
+			return &domain.PullRequestPage{Items: []domain.PullRequest{}}, nil
+		}
+		filter.AuthorIDs = teamAuthorIDs
+	}
+
+	return s.prRepo.List(ctx, filter)
+}
+
+func prExistsError(ctx context.Context, prID string) error {
+	return domainError(ctx, domain.ErrorCodePRExists, "PR id already exists").WithDetail("pull_request_id", prID)
+}
+
+func preconditionFailedError(ctx context.Context, prID string, expectedVersion int64) error {
+	return domainError(ctx, domain.ErrorCodePreconditionFailed, "PR was modified since version %d", expectedVersion).
+		WithDetail("pull_request_id", prID)
+}
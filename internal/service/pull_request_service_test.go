@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/guverz/pr-reviewer-service/internal/domain"
+	"github.com/guverz/pr-reviewer-service/internal/metrics"
+	"github.com/guverz/pr-reviewer-service/internal/repository"
+)
+
+// failingCreateRepository отказывает в создании PR, остальные операции передаёт дальше
+type failingCreateRepository struct {
+	repository.PullRequestRepository
+}
+
+func (failingCreateRepository) Create(context.Context, domain.PullRequest) error {
+	return errors.New("storage is unavailable")
+}
+
+func TestFailedCreateDoesNotAdvanceRotation(t *testing.T) {
+	ts := newTestServices(t)
+	ctx := context.Background()
+	ts.createTeam(t, "backend", "u1", "u2", "u3", "u4")
+	if _, err := ts.teams.SetReviewerStrategy(ctx, "backend", domain.ReviewerStrategyRoundRobin); err != nil {
+		t.Fatalf("set strategy: %v", err)
+	}
+
+	selector := NewReviewerSelector(ts.repos.PullRequest, ts.repos.Availability, ts.repos.Rotation)
+	failing := NewPullRequestService(failingCreateRepository{ts.repos.PullRequest}, ts.repos.User, ts.repos.Transaction,
+		selector, NewAuthorizer(ts.repos.Role), metrics.New())
+	if _, err := failing.CreatePR(ctx, "pr-1", "feature", "u1", nil); err == nil {
+		t.Fatal("expected create to fail")
+	}
+
+	rotation, err := ts.teams.GetReviewerRotation(ctx, "backend")
+	if err != nil {
+		t.Fatalf("get rotation: %v", err)
+	}
+	if rotation.LastUserID != "" {
+		t.Fatalf("failed create advanced the rotation to %q", rotation.LastUserID)
+	}
+
+	// Следующий успешный PR получает первых участников очереди
+	pr, err := ts.prs.CreatePR(ctx, "pr-1", "feature", "u1", nil)
+	if err != nil {
+		t.Fatalf("create pr: %v", err)
+	}
+	if len(pr.AssignedReviewers) != 2 || pr.AssignedReviewers[0] != "u2" || pr.AssignedReviewers[1] != "u3" {
+		t.Fatalf("unexpected reviewers %v", pr.AssignedReviewers)
+	}
+}
+
+func TestConcurrentCreatePR(t *testing.T) {
+	ts := newTestServices(t)
+	ts.createTeam(t, "backend", "u1", "u2", "u3")
+
+	const workers = 20
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		created int
+		exists  int
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Половина горутин создаёт один и тот же PR, остальные — свои
+			prID := "pr-shared"
+			if i%2 == 1 {
+				prID = fmt.Sprintf("pr-%d", i)
+			}
+			_, err := ts.prs.CreatePR(context.Background(), prID, prID, "u1", nil)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				created++
+			case errors.Is(err, domain.ErrorCodePRExists):
+				exists++
+			default:
+				t.Errorf("create %s: %v", prID, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if created != workers/2+1 || exists != workers/2-1 {
+		t.Fatalf("created %d, PR_EXISTS %d", created, exists)
+	}
+}
+
+func TestConcurrentUpdatePR(t *testing.T) {
+	ts := newTestServices(t)
+	ctx := context.Background()
+	ts.createTeam(t, "backend", "u1", "u2", "u3")
+	if _, err := ts.prs.CreatePR(ctx, "pr-1", "feature", "u1", nil); err != nil {
+		t.Fatalf("create pr: %v", err)
+	}
+
+	const workers = 20
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := ts.prs.updatePR(ctx, "pr-1", 0, nil, func(_ context.Context, pr *domain.PullRequest) (bool, error) {
+				pr.Reassignments = append(pr.Reassignments, domain.ReviewerReassignment{ActorID: fmt.Sprintf("w%d", i)})
+				return true, nil
+			})
+			if err != nil {
+				t.Errorf("update %d: %v", i, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// Ни одно изменение не потеряно: каждое применено к актуальной версии
+	pr, err := ts.repos.PullRequest.GetByID(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("get pr: %v", err)
+	}
+	if len(pr.Reassignments) != workers || pr.Version != workers+1 {
+		t.Fatalf("got %d reassignments at version %d, want %d at %d", len(pr.Reassignments), pr.Version, workers, workers+1)
+	}
+}
+
+func TestConcurrentCreatePRRoundRobin(t *testing.T) {
+	ts := newTestServices(t)
+	ctx := context.Background()
+	ts.createTeam(t, "backend", "author", "r1", "r2", "r3", "r4")
+	if _, err := ts.teams.SetReviewerStrategy(ctx, "backend", domain.ReviewerStrategyRoundRobin); err != nil {
+		t.Fatalf("set strategy: %v", err)
+	}
+
+	prIDs := []string{"pr-1", "pr-2"}
+	prs := make([]*domain.PullRequest, len(prIDs))
+	errs := make([]error, len(prIDs))
+	var wg sync.WaitGroup
+	for i, prID := range prIDs {
+		wg.Add(1)
+		go func(i int, prID string) {
+			defer wg.Done()
+			prs[i], errs[i] = ts.prs.CreatePR(context.Background(), prID, prID, "author", nil)
+		}(i, prID)
+	}
+	wg.Wait()
+
+	seen := make(map[string]bool)
+	for i := range prIDs {
+		if errs[i] != nil {
+			t.Fatalf("create %s: %v", prIDs[i], errs[i])
+		}
+		for _, reviewerID := range prs[i].AssignedReviewers {
+			if seen[reviewerID] {
+				t.Fatalf("reviewer %s assigned to both PRs: %v, %v", reviewerID, prs[0].AssignedReviewers, prs[1].AssignedReviewers)
+			}
+			seen[reviewerID] = true
+		}
+	}
+	if len(seen) != 4 {
+		t.Fatalf("expected 4 distinct reviewers, got %v", seen)
+	}
+}
+
+func TestSaveRotationConflictKeepsCause(t *testing.T) {
+	ts := newTestServices(t)
+	ctx := context.Background()
+	selector := NewReviewerSelector(ts.repos.PullRequest, ts.repos.Availability, ts.repos.Rotation)
+
+	stale := domain.ReviewerRotation{TeamName: "backend", Strategy: domain.ReviewerStrategyRoundRobin}
+	if err := ts.repos.Rotation.Save(ctx, stale); err != nil {
+		t.Fatalf("save rotation: %v", err)
+	}
+
+	// Версия 0 уже устарела: ротацию сохранил параллельный запрос
+	err := selector.SaveRotation(ctx, &stale)
+	if !errors.Is(err, domain.ErrorCodeConcurrentUpdate) || !errors.Is(err, repository.ErrVersionConflict) {
+		t.Fatalf("expected CONCURRENT_UPDATE wrapping ErrVersionConflict, got %v", err)
+	}
+}
+
+func TestListPRsRejectsMismatchedCursor(t *testing.T) {
+	ts := newTestServices(t)
+	ctx := context.Background()
+	ts.createTeam(t, "backend", "u1", "u2")
+	for _, prID := range []string{"pr-1", "pr-2", "pr-3"} {
+		if _, err := ts.prs.CreatePR(ctx, prID, prID, "u1", nil); err != nil {
+			t.Fatalf("create %s: %v", prID, err)
+		}
+	}
+
+	asc := domain.PullRequestFilter{SortBy: domain.PullRequestSortByID, Order: domain.SortOrderAsc, Limit: 1}
+	page, err := ts.prs.ListPRs(ctx, asc, "")
+	if err != nil || page.Next == nil {
+		t.Fatalf("first page: %+v, %v", page, err)
+	}
+
+	tests := []struct {
+		name    string
+		sortBy  domain.PullRequestSortField
+		order   domain.SortOrder
+		wantErr bool
+	}{
+		{name: "same sort", sortBy: domain.PullRequestSortByID, order: domain.SortOrderAsc},
+		{name: "other order", sortBy: domain.PullRequestSortByID, order: domain.SortOrderDesc, wantErr: true},
+		{name: "other field", sortBy: domain.PullRequestSortByCreatedAt, order: domain.SortOrderAsc, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := domain.PullRequestFilter{SortBy: tt.sortBy, Order: tt.order, Limit: 1, After: page.Next}
+			_, err := ts.prs.ListPRs(ctx, filter, "")
+			if gotErr := errors.Is(err, domain.ErrorCodeValidation); gotErr != tt.wantErr || (!tt.wantErr && err != nil) {
+				t.Fatalf("list: %v", err)
+			}
+		})
+	}
+}
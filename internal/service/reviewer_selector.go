@@ -1,60 +1,263 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"math/rand"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/guverz/pr-reviewer-service/internal/domain"
+	"github.com/guverz/pr-reviewer-service/internal/repository"
 )
 
 // ReviewerSelector выбирает ревьюеров из команды
 type ReviewerSelector struct {
+	prRepo           repository.PullRequestRepository
+	availabilityRepo repository.AvailabilityRepository
+	rotationRepo     repository.RotationRepository
+
+	// rand.Rand не потокобезопасен, а выбор идёт из параллельных запросов
+	mu  sync.Mutex
 	rng *rand.Rand
 }
 
-func NewReviewerSelector() *ReviewerSelector {
+// ReviewerSelection — выбранные ревьюеры и причина, если их меньше запрошенного
+type ReviewerSelection struct {
+	Reviewers []string
+	Shortage  domain.ReviewerShortage
+	// MatchedSkills — совпавшие с требуемыми теги для каждого выбранного ревьюера, у кого они есть
+	MatchedSkills map[string][]string
+	// Rotation — ротация команды со сдвинутым курсором round-robin; nil, если очередь не сдвигалась.
+	// Её сохраняют через SaveRotation после записи PR в той же транзакции.
+	Rotation *domain.ReviewerRotation
+}
+
+func NewReviewerSelector(
+	prRepo repository.PullRequestRepository,
+	availabilityRepo repository.AvailabilityRepository,
+	rotationRepo repository.RotationRepository,
+) *ReviewerSelector {
 	return &ReviewerSelector{
-		rng: rand.New(rand.NewSource(time.Now().UnixNano())),
+		prRepo:           prRepo,
+		availabilityRepo: availabilityRepo,
+		rotationRepo:     rotationRepo,
+		rng:              rand.New(rand.NewSource(time.Now().UnixNano())),
 	}
 }
 
-// SelectReviewers выбирает до 2 активных ревьюеров из команды, исключая автора
-func (rs *ReviewerSelector) SelectReviewers(teamMembers []domain.User, excludeUserID string, maxCount int) []string {
+// SelectReviewers выбирает до 2 активных ревьюеров из команды teamName, исключая автора,
+// тех, кто сейчас в отпуске или иначе недоступен, и тех, у кого исчерпан лимит открытых ревью.
+// Порядок выбора задаёт стратегия команды: при random кандидаты с большим числом совпавших
+// requiredSkills выбираются первыми, поэтому при наличии эксперта хотя бы одно место достаётся ему,
+// а при равенстве выбор случаен; при round_robin участники назначаются строго по очереди.
+// rotation — текущая ротация команды; nil означает прочитать её из хранилища. Курсор
+// в хранилище не сдвигается: новое значение возвращается в ReviewerSelection.Rotation.
+func (rs *ReviewerSelector) SelectReviewers(
+	ctx context.Context,
+	teamName string,
+	rotation *domain.ReviewerRotation,
+	teamMembers []domain.User,
+	excludeUserID string,
+	requiredSkills []string,
+	maxCount int,
+) (ReviewerSelection, error) {
 	if maxCount <= 0 {
 		maxCount = 2
 	}
 
-	// Фильтруем активных пользователей, исключая автора
-	candidates := make([]domain.User, 0)
+	unavailable, err := rs.unavailableUsers(ctx, time.Now())
+	if err != nil {
+		return ReviewerSelection{}, err
+	}
+
+	// Фильтруем активных и доступных пользователей, исключая автора
+	var skippedUnavailable bool
+	available := make([]domain.User, 0)
 	for _, member := range teamMembers {
-		if member.IsActive && member.ID != excludeUserID {
-			candidates = append(candidates, member)
+		if !member.IsActive || member.ID == excludeUserID {
+			continue
+		}
+		if unavailable[member.ID] {
+			skippedUnavailable = true
+			continue
 		}
+		available = append(available, member)
 	}
 
-	if len(candidates) == 0 {
-		return []string{}
+	// Отбрасываем тех, у кого уже максимум открытых ревью
+	candidates, skippedAtCapacity, err := rs.withCapacity(ctx, available)
+	if err != nil {
+		return ReviewerSelection{}, err
 	}
 
-	// Выбираем случайных ревьюеров
 	count := len(candidates)
 	if count > maxCount {
 		count = maxCount
 	}
 
-	// Перемешиваем кандидатов
+	selection := ReviewerSelection{Reviewers: make([]string, 0, count)}
+	if count > 0 {
+		if rotation == nil {
+			rotation, err = loadRotation(ctx, rs.rotationRepo, teamName)
+			if err != nil {
+				return ReviewerSelection{}, err
+			}
+		}
+
+		var chosen []domain.User
+		chosen, selection.Rotation = rs.choose(*rotation, candidates, requiredSkills, count)
+		for _, reviewer := range chosen {
+			selection.Reviewers = append(selection.Reviewers, reviewer.ID)
+			if skills := domain.MatchSkills(reviewer.Skills, requiredSkills); len(skills) > 0 {
+				if selection.MatchedSkills == nil {
+					selection.MatchedSkills = make(map[string][]string)
+				}
+				selection.MatchedSkills[reviewer.ID] = skills
+			}
+		}
+	}
+
+	if count < maxCount {
+		switch {
+		case skippedAtCapacity:
+			selection.Shortage = domain.ReviewerShortageAtCapacity
+		case skippedUnavailable:
+			selection.Shortage = domain.ReviewerShortageUnavailable
+		default:
+			selection.Shortage = domain.ReviewerShortageNoCandidates
+		}
+	}
+
+	return selection, nil
+}
+
+// choose выбирает count ревьюеров из candidates (count не больше их числа) по стратегии команды.
+// При round_robin возвращает также ротацию со сдвинутым курсором.
+func (rs *ReviewerSelector) choose(rotation domain.ReviewerRotation, candidates []domain.User, requiredSkills []string, count int) ([]domain.User, *domain.ReviewerRotation) {
+	if rotation.Strategy != domain.ReviewerStrategyRoundRobin {
+		return rs.rankBySkills(candidates, requiredSkills)[:count], nil
+	}
+
+	chosen := nextInRotation(candidates, rotation.LastUserID, count)
+	rotation.LastUserID = chosen[len(chosen)-1].ID
+	rotation.UpdatedAt = time.Now()
+	return chosen, &rotation
+}
+
+// SaveRotation сохраняет ротацию, сдвинутую SelectReviewers; nil ничего не меняет.
+// Вызывается после записи PR в той же транзакции, поэтому неудавшаяся запись не сдвигает очередь,
+// а курсор сохраняется compare-and-swap и параллельные выборы не получают одну позицию.
+// Ошибка конфликта оборачивает repository.ErrVersionConflict, чтобы вызывающий мог
+// повторить выбор с перечитанной ротацией.
+func (rs *ReviewerSelector) SaveRotation(ctx context.Context, rotation *domain.ReviewerRotation) error {
+	if rotation == nil {
+		return nil
+	}
+
+	err := rs.rotationRepo.Save(ctx, *rotation)
+	if errors.Is(err, repository.ErrVersionConflict) {
+		conflict := domainError(ctx, domain.ErrorCodeConcurrentUpdate, "reviewer rotation was modified concurrently, retry the request").
+			WithDetail("team_name", rotation.TeamName)
+		conflict.Err = err
+		return conflict
+	}
+	return err
+}
+
+// rankBySkills перемешивает кандидатов и упорядочивает их по убыванию числа совпавших тегов;
+// стабильная сортировка сохраняет случайный порядок при равенстве
+func (rs *ReviewerSelector) rankBySkills(candidates []domain.User, requiredSkills []string) []domain.User {
 	shuffled := make([]domain.User, len(candidates))
 	copy(shuffled, candidates)
+	rs.mu.Lock()
 	rs.rng.Shuffle(len(shuffled), func(i, j int) {
 		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
 	})
+	rs.mu.Unlock()
+
+	if len(requiredSkills) > 0 {
+		matched := make(map[string]int, len(shuffled))
+		for _, candidate := range shuffled {
+			matched[candidate.ID] = len(domain.MatchSkills(candidate.Skills, requiredSkills))
+		}
+		sort.SliceStable(shuffled, func(i, j int) bool {
+			return matched[shuffled[i].ID] > matched[shuffled[j].ID]
+		})
+	}
+	return shuffled
+}
+
+// nextInRotation возвращает count кандидатов по очереди: в порядке user_id,
+// начиная со следующего после lastUserID и с переходом в начало списка
+func nextInRotation(candidates []domain.User, lastUserID string, count int) []domain.User {
+	ordered := make([]domain.User, len(candidates))
+	copy(ordered, candidates)
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].ID < ordered[j].ID
+	})
 
-	// Берём первых count
-	reviewers := make([]string, 0, count)
+	start := sort.Search(len(ordered), func(i int) bool {
+		return ordered[i].ID > lastUserID
+	})
+	chosen := make([]domain.User, 0, count)
 	for i := 0; i < count; i++ {
-		reviewers = append(reviewers, shuffled[i].ID)
+		chosen = append(chosen, ordered[(start+i)%len(ordered)])
+	}
+	return chosen
+}
+
+// loadRotation возвращает ротацию команды или ротацию по умолчанию, если она не сохранялась
+func loadRotation(ctx context.Context, repo repository.RotationRepository, teamName string) (*domain.ReviewerRotation, error) {
+	rotation, err := repo.Get(ctx, teamName)
+	if errors.Is(err, repository.ErrNotFound) {
+		defaultRotation := domain.DefaultReviewerRotation(teamName)
+		return &defaultRotation, nil
+	}
+	return rotation, err
+}
+
+// unavailableUsers возвращает пользователей, у которых в момент at идёт интервал недоступности
+func (rs *ReviewerSelector) unavailableUsers(ctx context.Context, at time.Time) (map[string]bool, error) {
+	windows, err := rs.availabilityRepo.ListActive(ctx, at)
+	if err != nil {
+		return nil, err
 	}
 
-	return reviewers
+	unavailable := make(map[string]bool, len(windows))
+	for _, window := range windows {
+		unavailable[window.UserID] = true
+	}
+	return unavailable, nil
 }
 
+// withCapacity оставляет пользователей, которым можно назначить ещё одно ревью,
+// и сообщает, был ли кто-то отброшен из-за лимита
+func (rs *ReviewerSelector) withCapacity(ctx context.Context, users []domain.User) ([]domain.User, bool, error) {
+	limitedIDs := make([]string, 0)
+	for _, user := range users {
+		if user.MaxOpenReviews > 0 {
+			limitedIDs = append(limitedIDs, user.ID)
+		}
+	}
+	if len(limitedIDs) == 0 {
+		return users, false, nil
+	}
+
+	openReviews, err := rs.prRepo.CountOpenReviews(ctx, limitedIDs)
+	if err != nil {
+		return nil, false, err
+	}
+
+	skipped := false
+	withCapacity := make([]domain.User, 0, len(users))
+	for _, user := range users {
+		if !user.HasCapacity(openReviews[user.ID]) {
+			skipped = true
+			continue
+		}
+		withCapacity = append(withCapacity, user)
+	}
+	return withCapacity, skipped, nil
+}
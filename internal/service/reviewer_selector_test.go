@@ -0,0 +1,94 @@
+package service
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/guverz/pr-reviewer-service/internal/domain"
+)
+
+func TestRankBySkills(t *testing.T) {
+	candidates := []domain.User{
+		{ID: "u1"},
+		{ID: "u2", Skills: []string{"go"}},
+		{ID: "u3", Skills: []string{"go", "sql"}},
+		{ID: "u4", Skills: []string{"k8s"}},
+	}
+
+	tests := []struct {
+		name     string
+		required []string
+		// groups — кандидаты по убыванию числа совпавших тегов; внутри группы порядок случаен
+		groups [][]string
+	}{
+		{"no required skills", nil, [][]string{{"u1", "u2", "u3", "u4"}}},
+		{"single skill", []string{"go"}, [][]string{{"u2", "u3"}, {"u1", "u4"}}},
+		{"more matches first", []string{"go", "sql"}, [][]string{{"u3"}, {"u2"}, {"u1", "u4"}}},
+		{"nobody matches", []string{"rust"}, [][]string{{"u1", "u2", "u3", "u4"}}},
+	}
+
+	selector := NewReviewerSelector(nil, nil, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Перемешивание случайно, поэтому проверяем порядок на нескольких прогонах
+			for run := 0; run < 20; run++ {
+				ranked := selector.rankBySkills(candidates, tt.required)
+				if len(ranked) != len(candidates) {
+					t.Fatalf("got %d candidates, want %d", len(ranked), len(candidates))
+				}
+
+				pos := 0
+				for _, group := range tt.groups {
+					for _, user := range ranked[pos : pos+len(group)] {
+						if !slices.Contains(group, user.ID) {
+							t.Fatalf("run %d: %s is out of its group in %v", run, user.ID, ids(ranked))
+						}
+					}
+					pos += len(group)
+				}
+			}
+		})
+	}
+}
+
+func TestNextInRotation(t *testing.T) {
+	users := func(userIDs ...string) []domain.User {
+		result := make([]domain.User, len(userIDs))
+		for i, userID := range userIDs {
+			result[i] = domain.User{ID: userID}
+		}
+		return result
+	}
+
+	tests := []struct {
+		name       string
+		candidates []domain.User
+		lastUserID string
+		count      int
+		want       []string
+	}{
+		{"empty cursor starts from the first id", users("u3", "u1", "u2"), "", 2, []string{"u1", "u2"}},
+		{"continues after the cursor", users("u1", "u2", "u3"), "u1", 2, []string{"u2", "u3"}},
+		{"wraps around after the last id", users("u1", "u2", "u3"), "u3", 2, []string{"u1", "u2"}},
+		{"wraps in the middle of a selection", users("u1", "u2", "u3"), "u2", 2, []string{"u3", "u1"}},
+		{"cursor user left the team", users("u1", "u3", "u4"), "u2", 2, []string{"u3", "u4"}},
+		{"cursor user was the last id and left", users("u1", "u2"), "u3", 2, []string{"u1", "u2"}},
+		{"single candidate", users("u2"), "u2", 1, []string{"u2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ids(nextInRotation(tt.candidates, tt.lastUserID, tt.count)); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func ids(users []domain.User) []string {
+	result := make([]string, len(users))
+	for i, user := range users {
+		result[i] = user.ID
+	}
+	return result
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+
+	"github.com/guverz/pr-reviewer-service/internal/domain"
+	"github.com/guverz/pr-reviewer-service/internal/repository"
+)
+
+type RoleService struct {
+	roleRepo   repository.RoleRepository
+	userRepo   repository.UserRepository
+	teamRepo   repository.TeamRepository
+	authorizer *Authorizer
+}
+
+func NewRoleService(
+	roleRepo repository.RoleRepository,
+	userRepo repository.UserRepository,
+	teamRepo repository.TeamRepository,
+	authorizer *Authorizer,
+) *RoleService {
+	return &RoleService{
+		roleRepo:   roleRepo,
+		userRepo:   userRepo,
+		teamRepo:   teamRepo,
+		authorizer: authorizer,
+	}
+}
+
+// AssignRole выдаёт пользователю роль. Для командных ролей команда обязательна, для глобальных — запрещена.
+func (s *RoleService) AssignRole(ctx context.Context, assignment domain.RoleAssignment) error {
+	ctx, span := tracer.Start(ctx, "RoleService.AssignRole")
+	defer span.End()
+
+	if err := s.authorizer.Authorize(ctx, domain.PermissionManageRoles, ""); err != nil {
+		return err
+	}
+
+	if !assignment.Role.IsValid() {
+		return domainError(ctx, domain.ErrorCodeValidation, "unknown role %q", assignment.Role)
+	}
+	if _, err := s.userRepo.GetByID(ctx, assignment.UserID); err != nil {
+		return lookupError(ctx, err, "user not found", "user_id", assignment.UserID)
+	}
+
+	if assignment.Role.IsTeamScoped() {
+		if _, err := s.teamRepo.GetByName(ctx, assignment.TeamName); err != nil {
+			return lookupError(ctx, err, "team not found", "team_name", assignment.TeamName)
+		}
+	} else {
+		assignment.TeamName = ""
+	}
+
+	return s.roleRepo.Assign(ctx, assignment)
+}
+
+// RevokeRole отзывает ранее выданную роль
+func (s *RoleService) RevokeRole(ctx context.Context, assignment domain.RoleAssignment) error {
+	ctx, span := tracer.Start(ctx, "RoleService.RevokeRole")
+	defer span.End()
+
+	if err := s.authorizer.Authorize(ctx, domain.PermissionManageRoles, ""); err != nil {
+		return err
+	}
+
+	if !assignment.Role.IsTeamScoped() {
+		assignment.TeamName = ""
+	}
+	if err := s.roleRepo.Revoke(ctx, assignment); err != nil {
+		return lookupError(ctx, err, "role assignment not found", "user_id", assignment.UserID)
+	}
+	return nil
+}
+
+// ListRoles возвращает роли пользователя userID или все выданные роли, если userID пуст
+func (s *RoleService) ListRoles(ctx context.Context, userID string) ([]domain.RoleAssignment, error) {
+	ctx, span := tracer.Start(ctx, "RoleService.ListRoles")
+	defer span.End()
+
+	if err := s.authorizer.Authorize(ctx, domain.PermissionManageRoles, ""); err != nil {
+		return nil, err
+	}
+
+	if userID == "" {
+		return s.roleRepo.List(ctx)
+	}
+	return s.roleRepo.ListByUser(ctx, userID)
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/guverz/pr-reviewer-service/internal/domain"
+	"github.com/guverz/pr-reviewer-service/internal/metrics"
+	"github.com/guverz/pr-reviewer-service/internal/repository/inmemory"
+)
+
+// testServices — сервисы поверх in-memory хранилища, как их собирает приложение.
+// Контекст без Identity считается системным вызовом, поэтому проверки прав проходят.
+type testServices struct {
+	repos *inmemory.Repositories
+	teams *TeamService
+	users *UserService
+	prs   *PullRequestService
+	admin *AdminService
+}
+
+func newTestServices(t *testing.T) *testServices {
+	t.Helper()
+
+	repos := inmemory.NewRepositories()
+	m := metrics.New()
+	selector := NewReviewerSelector(repos.PullRequest, repos.Availability, repos.Rotation)
+	authorizer := NewAuthorizer(repos.Role)
+	return &testServices{
+		repos: repos,
+		teams: NewTeamService(repos.Team, repos.User, repos.PullRequest, repos.Role, repos.Rotation, repos.Transaction, selector, authorizer, m),
+		users: NewUserService(repos.User, repos.Team, repos.Transaction, authorizer),
+		prs:   NewPullRequestService(repos.PullRequest, repos.User, repos.Transaction, selector, authorizer, m),
+		admin: NewAdminService(repos.Team, repos.User, repos.PullRequest, repos.Role, repos.Availability, repos.Rotation, repos.Transaction, authorizer),
+	}
+}
+
+// createTeam создаёт команду из активных участников с указанными ID
+func (ts *testServices) createTeam(t *testing.T, teamName string, userIDs ...string) {
+	t.Helper()
+
+	team := domain.Team{Name: teamName}
+	for _, userID := range userIDs {
+		team.Members = append(team.Members, domain.TeamMember{UserID: userID, Username: userID, IsActive: true})
+	}
+	if _, err := ts.teams.CreateTeam(context.Background(), team); err != nil {
+		t.Fatalf("create team %s: %v", teamName, err)
+	}
+}
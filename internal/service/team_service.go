@@ -2,42 +2,82 @@ package service
 
 import (
 	"context"
+	"errors"
+	"sort"
+	"time"
 
+	"github.com/rs/zerolog"
+
+	"github.com/guverz/pr-reviewer-service/internal/auth"
 	"github.com/guverz/pr-reviewer-service/internal/domain"
+	"github.com/guverz/pr-reviewer-service/internal/metrics"
 	"github.com/guverz/pr-reviewer-service/internal/repository"
 )
 
 type TeamService struct {
-	teamRepo repository.TeamRepository
-	userRepo repository.UserRepository
-	txMgr    repository.TransactionManager
+	teamRepo         repository.TeamRepository
+	userRepo         repository.UserRepository
+	prRepo           repository.PullRequestRepository
+	roleRepo         repository.RoleRepository
+	rotationRepo     repository.RotationRepository
+	txMgr            repository.TransactionManager
+	reviewerSelector *ReviewerSelector
+	authorizer       *Authorizer
+	metrics          *metrics.Metrics
 }
 
 func NewTeamService(
 	teamRepo repository.TeamRepository,
 	userRepo repository.UserRepository,
+	prRepo repository.PullRequestRepository,
+	roleRepo repository.RoleRepository,
+	rotationRepo repository.RotationRepository,
 	txMgr repository.TransactionManager,
+	reviewerSelector *ReviewerSelector,
+	authorizer *Authorizer,
+	metrics *metrics.Metrics,
 ) *TeamService {
 	return &TeamService{
-		teamRepo: teamRepo,
-		userRepo: userRepo,
-		txMgr:    txMgr,
+		teamRepo:         teamRepo,
+		userRepo:         userRepo,
+		prRepo:           prRepo,
+		roleRepo:         roleRepo,
+		rotationRepo:     rotationRepo,
+		txMgr:            txMgr,
+		reviewerSelector: reviewerSelector,
+		authorizer:       authorizer,
+		metrics:          metrics,
 	}
 }
 
 // CreateTeam создаёт команду и обновляет/создаёт пользователей
 func (s *TeamService) CreateTeam(ctx context.Context, team domain.Team) (*domain.Team, error) {
+	ctx, span := tracer.Start(ctx, "TeamService.CreateTeam")
+	defer span.End()
+
+	if err := s.authorizer.Authorize(ctx, domain.PermissionManageTeams, ""); err != nil {
+		return nil, err
+	}
+
 	// Проверяем, существует ли команда
-	existing, err := s.teamRepo.GetByName(ctx, team.Name)
-	if err == nil && existing != nil {
-		return nil, domain.NewDomainError(domain.ErrorCodeTeamExists, "team_name already exists")
+	_, err := s.teamRepo.GetByName(ctx, team.Name)
+	switch {
+	case err == nil:
+		return nil, domainError(ctx, domain.ErrorCodeTeamExists, "team_name already exists").
+			WithDetail("team_name", team.Name)
+	case !errors.Is(err, repository.ErrNotFound):
+		return nil, err
 	}
 
 	// Создаём команду и пользователей в транзакции
 	var createdTeam *domain.Team
 	err = s.txMgr.WithinTransaction(ctx, func(txCtx context.Context) error {
-		// Создаём команду
+		// Создаём команду; её могли создать параллельно после проверки выше
 		if err := s.teamRepo.Create(txCtx, team); err != nil {
+			if errors.Is(err, repository.ErrAlreadyExists) {
+				return domainError(ctx, domain.ErrorCodeTeamExists, "team_name already exists").
+					WithDetail("team_name", team.Name)
+			}
 			return err
 		}
 
@@ -60,10 +100,494 @@ func (s *TeamService) CreateTeam(ctx context.Context, team domain.Team) (*domain
 
 // GetTeam получает команду по имени
 func (s *TeamService) GetTeam(ctx context.Context, teamName string) (*domain.Team, error) {
+	ctx, span := tracer.Start(ctx, "TeamService.GetTeam")
+	defer span.End()
+
 	team, err := s.teamRepo.GetByName(ctx, teamName)
 	if err != nil {
-		return nil, domain.NewDomainError(domain.ErrorCodeNotFound, "team not found")
+		return nil, lookupError(ctx, err, "team not found", "team_name", teamName)
 	}
 	return team, nil
 }
 
+// SyncTeam приводит состав команды к desired: создаёт команду, если её нет,
+// добавляет, удаляет, переименовывает и (де)активирует участников.
+// Участники, перешедшие из другой команды, удаляются из её состава.
+// Удалённые из состава пользователи сохраняются без команды; если они ревьюят открытые PR,
+// синхронизация отклоняется с TEAM_HAS_OPEN_REVIEWS — ревью нужно сначала переназначить.
+// Повторный вызов с тем же составом ничего не меняет и возвращает пустой дифф.
+func (s *TeamService) SyncTeam(ctx context.Context, desired domain.Team) (*domain.TeamDiff, *domain.Team, error) {
+	ctx, span := tracer.Start(ctx, "TeamService.SyncTeam")
+	defer span.End()
+
+	if err := s.authorizer.Authorize(ctx, domain.PermissionManageTeams, ""); err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		diff       domain.TeamDiff
+		syncedTeam *domain.Team
+	)
+	err := s.txMgr.WithinTransaction(ctx, func(txCtx context.Context) error {
+		// Текущий состав читаем в той же транзакции, что и запись, иначе дифф
+		// может устареть из-за параллельной синхронизации
+		current, err := s.teamRepo.GetByName(txCtx, desired.Name)
+		if errors.Is(err, repository.ErrNotFound) {
+			current = nil
+		} else if err != nil {
+			return err
+		}
+
+		diff = domain.DiffTeam(current, desired)
+		if diff.IsEmpty() {
+			syncedTeam = current
+			return nil
+		}
+
+		addedIDs := make([]string, len(diff.Added))
+		for i, member := range diff.Added {
+			addedIDs[i] = member.UserID
+		}
+		removedIDs := make([]string, len(diff.Removed))
+		for i, member := range diff.Removed {
+			removedIDs[i] = member.UserID
+		}
+
+		// Снятые участники с открытыми ревью оставили бы их за пределами команды;
+		// проверяем до любых изменений, потому что отката нет
+		openPRs, err := s.openReviews(txCtx, removedIDs)
+		if err != nil {
+			return err
+		}
+		if len(openPRs) > 0 {
+			return domainError(ctx, domain.ErrorCodeTeamHasOpenReviews,
+				"removed members review %d open pull requests, reassign them first", len(openPRs)).
+				WithDetail("team_name", desired.Name).
+				WithDetail("open_pull_requests", len(openPRs))
+		}
+
+		if current == nil {
+			if err := s.teamRepo.Create(txCtx, desired); err != nil {
+				if errors.Is(err, repository.ErrAlreadyExists) {
+					return domainError(ctx, domain.ErrorCodeTeamExists, "team_name already exists").
+						WithDetail("team_name", desired.Name)
+				}
+				return err
+			}
+		} else if err := s.teamRepo.SetMembers(txCtx, desired.Name, desired.Members); err != nil {
+			return err
+		}
+
+		// Новые участники могли состоять в другой команде
+		movedUsers, err := s.userRepo.GetByIDs(txCtx, addedIDs)
+		if err != nil {
+			return err
+		}
+		for _, user := range movedUsers {
+			if user.TeamName == "" || user.TeamName == desired.Name {
+				continue
+			}
+			if err := s.teamRepo.RemoveMember(txCtx, user.TeamName, user.ID); err != nil {
+				return err
+			}
+		}
+
+		if err := s.userRepo.UpsertTeamMembers(txCtx, desired.Name, desired.Members); err != nil {
+			return err
+		}
+		if err := s.removeMembers(txCtx, desired.Name, removedIDs); err != nil {
+			return err
+		}
+
+		syncedTeam, err = s.teamRepo.GetByName(txCtx, desired.Name)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if diff.IsEmpty() {
+		return &diff, syncedTeam, nil
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("team_name", desired.Name).
+		Bool("created", diff.Created).
+		Int("added", len(diff.Added)).
+		Int("removed", len(diff.Removed)).
+		Int("renamed", len(diff.Renamed)).
+		Int("activated", len(diff.Activated)).
+		Int("deactivated", len(diff.Deactivated)).
+		Msg("team synced")
+
+	return &diff, syncedTeam, nil
+}
+
+// ListTeams возвращает все команды, отсортированные по имени
+func (s *TeamService) ListTeams(ctx context.Context) ([]domain.Team, error) {
+	ctx, span := tracer.Start(ctx, "TeamService.ListTeams")
+	defer span.End()
+
+	return s.teamRepo.List(ctx)
+}
+
+// RenameTeam переименовывает команду и переносит в неё всех участников
+func (s *TeamService) RenameTeam(ctx context.Context, oldName, newName string) (*domain.Team, error) {
+	ctx, span := tracer.Start(ctx, "TeamService.RenameTeam")
+	defer span.End()
+
+	if err := s.authorizer.Authorize(ctx, domain.PermissionManageTeams, ""); err != nil {
+		return nil, err
+	}
+
+	team, err := s.teamRepo.GetByName(ctx, oldName)
+	if err != nil {
+		return nil, lookupError(ctx, err, "team not found", "team_name", oldName)
+	}
+	if oldName == newName {
+		return team, nil
+	}
+	_, err = s.teamRepo.GetByName(ctx, newName)
+	switch {
+	case err == nil:
+		return nil, domainError(ctx, domain.ErrorCodeTeamExists, "team_name already exists").
+			WithDetail("team_name", newName)
+	case !errors.Is(err, repository.ErrNotFound):
+		return nil, err
+	}
+
+	var renamedTeam *domain.Team
+	err = s.txMgr.WithinTransaction(ctx, func(txCtx context.Context) error {
+		if err := s.teamRepo.Rename(txCtx, oldName, newName); err != nil {
+			if errors.Is(err, repository.ErrAlreadyExists) {
+				return domainError(ctx, domain.ErrorCodeTeamExists, "team_name already exists").
+					WithDetail("team_name", newName)
+			}
+			return err
+		}
+
+		if err := s.userRepo.RenameTeam(txCtx, oldName, newName); err != nil {
+			return err
+		}
+
+		if err := s.roleRepo.RenameTeam(txCtx, oldName, newName); err != nil {
+			return err
+		}
+
+		if err := s.rotationRepo.RenameTeam(txCtx, oldName, newName); err != nil {
+			return err
+		}
+
+		var err error
+		renamedTeam, err = s.teamRepo.GetByName(txCtx, newName)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return renamedTeam, nil
+}
+
+// DeleteTeam удаляет команду. Участники остаются пользователями без команды,
+// потому что на них ссылаются PR и история переназначений.
+// Если участники держат открытые ревью, удаление возможно только с указанием reassignTo —
+// команды, среди активных участников которой будут выбраны замены.
+// Возвращает ID PR, в которых были переназначены ревьюеры.
+func (s *TeamService) DeleteTeam(ctx context.Context, teamName, reassignTo string) ([]string, error) {
+	ctx, span := tracer.Start(ctx, "TeamService.DeleteTeam")
+	defer span.End()
+
+	if err := s.authorizer.Authorize(ctx, domain.PermissionManageTeams, ""); err != nil {
+		return nil, err
+	}
+
+	var reassignedPRs []string
+	err := s.txMgr.WithinTransaction(ctx, func(txCtx context.Context) error {
+		// Состав, открытые PR и кандидатов читаем в транзакции, иначе замены
+		// могут быть выбраны по устаревшим данным
+		if _, err := s.teamRepo.GetByName(txCtx, teamName); err != nil {
+			return lookupError(ctx, err, "team not found", "team_name", teamName)
+		}
+		members, err := s.userRepo.ListByTeam(txCtx, teamName, false)
+		if err != nil {
+			return err
+		}
+
+		// Собираем открытые PR, где ревьюерами назначены участники команды
+		memberIDs := make(map[string]bool, len(members))
+		ids := make([]string, len(members))
+		for i, member := range members {
+			memberIDs[member.ID] = true
+			ids[i] = member.ID
+		}
+		openPRs, err := s.openReviews(txCtx, ids)
+		if err != nil {
+			return err
+		}
+
+		var (
+			candidates []domain.User
+			rotation   *domain.ReviewerRotation
+		)
+		if len(openPRs) > 0 {
+			if reassignTo == "" {
+				return domainError(ctx, domain.ErrorCodeTeamHasOpenReviews,
+					"team members review %d open pull requests, reassign_to_team is required", len(openPRs)).
+					WithDetail("open_pull_requests", len(openPRs))
+			}
+			if reassignTo == teamName {
+				return domainError(ctx, domain.ErrorCodeTeamHasOpenReviews, "reassign_to_team must differ from team_name")
+			}
+			if _, err := s.teamRepo.GetByName(txCtx, reassignTo); errors.Is(err, repository.ErrNotFound) {
+				return domainError(ctx, domain.ErrorCodeNotFound, "reassign_to_team not found").
+					WithDetail("team_name", reassignTo)
+			} else if err != nil {
+				return err
+			}
+			candidates, err = s.userRepo.ListByTeam(txCtx, reassignTo, true)
+			if err != nil {
+				return err
+			}
+			rotation, err = loadRotation(txCtx, s.rotationRepo, reassignTo)
+			if err != nil {
+				return err
+			}
+		}
+
+		// PR обходятся по ID, чтобы очередь round-robin шла в предсказуемом порядке
+		prIDs := make([]string, 0, len(openPRs))
+		for prID := range openPRs {
+			prIDs = append(prIDs, prID)
+		}
+		sort.Strings(prIDs)
+
+		actorID := auth.ActorFromContext(ctx)
+		updated := make([]domain.PullRequest, 0, len(openPRs))
+		var advanced *domain.ReviewerRotation
+		for _, prID := range prIDs {
+			pr := openPRs[prID]
+			next, err := s.replaceReviewers(txCtx, &pr, memberIDs, reassignTo, rotation, candidates, actorID)
+			if err != nil {
+				return err
+			}
+			if next != nil {
+				rotation, advanced = next, next
+			}
+			updated = append(updated, pr)
+		}
+		// Все PR сохраняются разом: конфликт версии в одном не оставит другие переписанными
+		if err := s.prRepo.UpdateMany(txCtx, updated); err != nil {
+			// PR изменился после чтения (например, его смержили): удаление нужно повторить
+			if errors.Is(err, repository.ErrVersionConflict) {
+				return domainError(ctx, domain.ErrorCodeConcurrentUpdate, "PR was modified concurrently, retry the request")
+			}
+			return err
+		}
+		if err := s.reviewerSelector.SaveRotation(txCtx, advanced); err != nil {
+			return err
+		}
+		reassignedPRs = make([]string, len(updated))
+		for i, pr := range updated {
+			reassignedPRs[i] = pr.ID
+		}
+
+		// Участники остаются пользователями без команды: на них ссылаются PR и история переназначений
+		if err := s.userRepo.RemoveFromTeam(txCtx, teamName, ids); err != nil {
+			return err
+		}
+
+		if err := s.roleRepo.DeleteByTeam(txCtx, teamName); err != nil {
+			return err
+		}
+
+		if err := s.rotationRepo.DeleteByTeam(txCtx, teamName); err != nil {
+			return err
+		}
+
+		return s.teamRepo.Delete(txCtx, teamName)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("team_name", teamName).
+		Str("reassign_to_team", reassignTo).
+		Strs("reassigned_pull_requests", reassignedPRs).
+		Msg("team deleted")
+
+	return reassignedPRs, nil
+}
+
+// removeMembers снимает пользователей с команды и отзывает их роли в ней
+func (s *TeamService) removeMembers(ctx context.Context, teamName string, userIDs []string) error {
+	for _, userID := range userIDs {
+		assignments, err := s.roleRepo.ListByUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		for _, assignment := range assignments {
+			if assignment.TeamName != teamName {
+				continue
+			}
+			if err := s.roleRepo.Revoke(ctx, assignment); err != nil {
+				return err
+			}
+		}
+	}
+
+	return s.userRepo.RemoveFromTeam(ctx, teamName, userIDs)
+}
+
+// openReviews возвращает открытые PR, где ревьюером назначен кто-то из userIDs
+func (s *TeamService) openReviews(ctx context.Context, userIDs []string) (map[string]domain.PullRequest, error) {
+	openPRs := make(map[string]domain.PullRequest)
+	for _, userID := range userIDs {
+		prs, err := s.prRepo.ListByReviewer(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		for _, pr := range prs {
+			if !pr.IsMerged() {
+				openPRs[pr.ID] = pr
+			}
+		}
+	}
+	return openPRs, nil
+}
+
+// GetReviewerRotation возвращает стратегию выбора ревьюеров команды и курсор очереди
+func (s *TeamService) GetReviewerRotation(ctx context.Context, teamName string) (*domain.ReviewerRotation, error) {
+	ctx, span := tracer.Start(ctx, "TeamService.GetReviewerRotation")
+	defer span.End()
+
+	if _, err := s.teamRepo.GetByName(ctx, teamName); err != nil {
+		return nil, lookupError(ctx, err, "team not found", "team_name", teamName)
+	}
+	return loadRotation(ctx, s.rotationRepo, teamName)
+}
+
+// maxRotationAttempts — сколько раз ротация перечитывается при конфликте версий
+const maxRotationAttempts = 10
+
+// SetReviewerStrategy меняет стратегию выбора ревьюеров команды. Курсор очереди сохраняется,
+// поэтому при возврате к round_robin очередь продолжается с того же места.
+func (s *TeamService) SetReviewerStrategy(ctx context.Context, teamName string, strategy domain.ReviewerStrategy) (*domain.ReviewerRotation, error) {
+	ctx, span := tracer.Start(ctx, "TeamService.SetReviewerStrategy")
+	defer span.End()
+
+	if !strategy.IsValid() {
+		return nil, domainError(ctx, domain.ErrorCodeValidation, "unknown reviewer strategy %q", strategy)
+	}
+
+	if _, err := s.teamRepo.GetByName(ctx, teamName); err != nil {
+		return nil, lookupError(ctx, err, "team not found", "team_name", teamName)
+	}
+
+	// Менять стратегию могут администраторы и лиды команды
+	if err := s.authorizer.Authorize(ctx, domain.PermissionEditReviewPolicy, teamName); err != nil {
+		return nil, err
+	}
+
+	// Курсор сдвигается параллельно с выбором ревьюеров, поэтому сохранение повторяется при конфликте
+	for attempt := 1; ; attempt++ {
+		var (
+			rotation *domain.ReviewerRotation
+			previous domain.ReviewerStrategy
+		)
+		err := s.txMgr.WithinTransaction(ctx, func(txCtx context.Context) error {
+			var err error
+			rotation, err = loadRotation(txCtx, s.rotationRepo, teamName)
+			if err != nil || rotation.Strategy == strategy {
+				return err
+			}
+
+			previous = rotation.Strategy
+			rotation.Strategy = strategy
+			rotation.UpdatedAt = time.Now()
+			if err := s.rotationRepo.Save(txCtx, *rotation); err != nil {
+				return err
+			}
+			rotation.Version++
+			return nil
+		})
+		switch {
+		case err == nil:
+			if previous != "" {
+				zerolog.Ctx(ctx).Info().
+					Str("team_name", teamName).
+					Str("previous_strategy", string(previous)).
+					Str("strategy", string(strategy)).
+					Msg("reviewer strategy changed")
+			}
+			return rotation, nil
+		case !errors.Is(err, repository.ErrVersionConflict):
+			return nil, err
+		case attempt == maxRotationAttempts:
+			return nil, domainError(ctx, domain.ErrorCodeConcurrentUpdate, "reviewer rotation was modified concurrently, retry the request").
+				WithDetail("team_name", teamName)
+		}
+	}
+}
+
+// replaceReviewers заменяет ревьюеров из removedIDs на кандидатов из команды teamName.
+// Если подходящего кандидата нет, ревьюер снимается и PR помечается как требующий ревьюеров.
+// Каждая замена записывается в историю PR; снятие без замены — с пустым NewReviewerID.
+// Выбор начинается с ротации rotation; сдвинутая ротация возвращается (nil, если очередь
+// не сдвигалась) и должна быть сохранена вызывающим после записи PR.
+func (s *TeamService) replaceReviewers(
+	ctx context.Context,
+	pr *domain.PullRequest,
+	removedIDs map[string]bool,
+	teamName string,
+	rotation *domain.ReviewerRotation,
+	candidates []domain.User,
+	actorID string,
+) (*domain.ReviewerRotation, error) {
+	var advanced *domain.ReviewerRotation
+	now := time.Now()
+	var shortage domain.ReviewerShortage
+	for _, reviewerID := range append([]string(nil), pr.AssignedReviewers...) {
+		if !removedIDs[reviewerID] {
+			continue
+		}
+
+		available := make([]domain.User, 0, len(candidates))
+		for _, candidate := range candidates {
+			if candidate.ID != pr.AuthorID && !pr.HasReviewer(candidate.ID) {
+				available = append(available, candidate)
+			}
+		}
+
+		reassignment := domain.ReviewerReassignment{
+			OldReviewerID: reviewerID,
+			ActorID:       actorID,
+			At:            now,
+		}
+		selection, err := s.reviewerSelector.SelectReviewers(ctx, teamName, rotation, available, "", pr.RequiredSkills, 1)
+		if err != nil {
+			return nil, err
+		}
+		if selection.Rotation != nil {
+			rotation, advanced = selection.Rotation, selection.Rotation
+		}
+		if len(selection.Reviewers) == 0 {
+			zerolog.Ctx(ctx).Warn().
+				Str("pull_request_id", pr.ID).
+				Str("reviewer_id", reviewerID).
+				Str("reason", string(selection.Shortage)).
+				Msg("no replacement candidate, reviewer removed")
+			pr.RemoveReviewer(reviewerID)
+			shortage = selection.Shortage
+		} else {
+			newReviewerID := selection.Reviewers[0]
+			pr.ReplaceReviewer(reviewerID, newReviewerID)
+			pr.SetMatchedSkills(newReviewerID, selection.MatchedSkills[newReviewerID])
+			reassignment.NewReviewerID = newReviewerID
+			s.metrics.ReviewerReassigned(metrics.ReassignReasonTeamDeleted)
+		}
+		pr.Reassignments = append(pr.Reassignments, reassignment)
+	}
+	pr.UpdateNeedMoreReviewers(shortage)
+	return advanced, nil
+}
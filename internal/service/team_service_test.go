@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/guverz/pr-reviewer-service/internal/domain"
+)
+
+// syncMembers синхронизирует команду к активным участникам с указанными ID
+func (ts *testServices) syncMembers(teamName string, userIDs ...string) (*domain.Team, error) {
+	team := domain.Team{Name: teamName}
+	for _, userID := range userIDs {
+		team.Members = append(team.Members, domain.TeamMember{UserID: userID, Username: userID, IsActive: true})
+	}
+	_, synced, err := ts.teams.SyncTeam(context.Background(), team)
+	return synced, err
+}
+
+func TestSyncTeamKeepsRemovedUsers(t *testing.T) {
+	ts := newTestServices(t)
+	ctx := context.Background()
+	ts.createTeam(t, "backend", "u1", "u2")
+
+	synced, err := ts.syncMembers("backend", "u1")
+	if err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if len(synced.Members) != 1 {
+		t.Fatalf("unexpected members %+v", synced.Members)
+	}
+
+	user, err := ts.users.GetUser(ctx, "u2")
+	if err != nil {
+		t.Fatalf("removed user must be kept: %v", err)
+	}
+	if user.TeamName != "" {
+		t.Fatalf("removed user still belongs to %q", user.TeamName)
+	}
+}
+
+func TestSyncTeamRefusesToRemoveReviewerOfOpenPR(t *testing.T) {
+	ts := newTestServices(t)
+	ctx := context.Background()
+	ts.createTeam(t, "backend", "u1", "u2")
+
+	pr, err := ts.prs.CreatePR(ctx, "pr-1", "feature", "u1", nil)
+	if err != nil {
+		t.Fatalf("create pr: %v", err)
+	}
+	if len(pr.AssignedReviewers) != 1 || pr.AssignedReviewers[0] != "u2" {
+		t.Fatalf("unexpected reviewers %v", pr.AssignedReviewers)
+	}
+
+	if _, err := ts.syncMembers("backend", "u1"); !errors.Is(err, domain.ErrorCodeTeamHasOpenReviews) {
+		t.Fatalf("expected %s, got %v", domain.ErrorCodeTeamHasOpenReviews, err)
+	}
+
+	// Отказ не должен оставить частично применённую синхронизацию
+	team, err := ts.teams.GetTeam(ctx, "backend")
+	if err != nil {
+		t.Fatalf("get team: %v", err)
+	}
+	if len(team.Members) != 2 {
+		t.Fatalf("team changed after refused sync: %+v", team.Members)
+	}
+	user, err := ts.users.GetUser(ctx, "u2")
+	if err != nil || user.TeamName != "backend" {
+		t.Fatalf("reviewer changed after refused sync: %+v, %v", user, err)
+	}
+}
+
+func TestConcurrentSyncsOfNewTeam(t *testing.T) {
+	ts := newTestServices(t)
+
+	const workers = 10
+	created := make(chan bool, workers)
+	errs := make(chan error, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			team := domain.Team{Name: "backend", Members: []domain.TeamMember{{UserID: "u1", Username: "u1", IsActive: true}}}
+			diff, _, err := ts.teams.SyncTeam(context.Background(), team)
+			if err != nil {
+				errs <- err
+				return
+			}
+			created <- diff.Created
+		}()
+	}
+	wg.Wait()
+	close(created)
+	close(errs)
+
+	for err := range errs {
+		t.Fatalf("sync: %v", err)
+	}
+	creations := 0
+	for c := range created {
+		if c {
+			creations++
+		}
+	}
+	// Дифф считается внутри транзакции, поэтому команду создаёт ровно одна синхронизация
+	if creations != 1 {
+		t.Fatalf("team created %d times", creations)
+	}
+}
+
+func TestDeleteTeamReassignsAllOpenReviews(t *testing.T) {
+	ts := newTestServices(t)
+	ctx := context.Background()
+	ts.createTeam(t, "backend", "u1", "u2")
+	ts.createTeam(t, "qa", "q1")
+
+	for _, prID := range []string{"pr-1", "pr-2"} {
+		if _, err := ts.prs.CreatePR(ctx, prID, prID, "u1", nil); err != nil {
+			t.Fatalf("create %s: %v", prID, err)
+		}
+	}
+
+	if _, err := ts.teams.DeleteTeam(ctx, "backend", ""); !errors.Is(err, domain.ErrorCodeTeamHasOpenReviews) {
+		t.Fatalf("expected %s, got %v", domain.ErrorCodeTeamHasOpenReviews, err)
+	}
+
+	reassigned, err := ts.teams.DeleteTeam(ctx, "backend", "qa")
+	if err != nil {
+		t.Fatalf("delete team: %v", err)
+	}
+	if len(reassigned) != 2 || reassigned[0] != "pr-1" || reassigned[1] != "pr-2" {
+		t.Fatalf("unexpected reassigned PRs %v", reassigned)
+	}
+	for _, prID := range reassigned {
+		pr, err := ts.repos.PullRequest.GetByID(ctx, prID)
+		if err != nil {
+			t.Fatalf("get %s: %v", prID, err)
+		}
+		if len(pr.AssignedReviewers) != 1 || pr.AssignedReviewers[0] != "q1" {
+			t.Fatalf("%s reviewers %v, want [q1]", prID, pr.AssignedReviewers)
+		}
+	}
+}
+
+func TestDeleteTeamAdvancesTargetRotationOnce(t *testing.T) {
+	ts := newTestServices(t)
+	ctx := context.Background()
+	ts.createTeam(t, "backend", "u1", "u2")
+	ts.createTeam(t, "qa", "q1", "q2", "q3")
+	if _, err := ts.teams.SetReviewerStrategy(ctx, "qa", domain.ReviewerStrategyRoundRobin); err != nil {
+		t.Fatalf("set strategy: %v", err)
+	}
+	for _, prID := range []string{"pr-1", "pr-2"} {
+		if _, err := ts.prs.CreatePR(ctx, prID, prID, "u1", nil); err != nil {
+			t.Fatalf("create %s: %v", prID, err)
+		}
+	}
+
+	if _, err := ts.teams.DeleteTeam(ctx, "backend", "qa"); err != nil {
+		t.Fatalf("delete team: %v", err)
+	}
+
+	// Замены идут по очереди qa: каждая видит курсор, сдвинутый предыдущей
+	for prID, want := range map[string]string{"pr-1": "q1", "pr-2": "q2"} {
+		pr, err := ts.repos.PullRequest.GetByID(ctx, prID)
+		if err != nil {
+			t.Fatalf("get %s: %v", prID, err)
+		}
+		if len(pr.AssignedReviewers) != 1 || pr.AssignedReviewers[0] != want {
+			t.Fatalf("%s reviewers %v, want [%s]", prID, pr.AssignedReviewers, want)
+		}
+	}
+	rotation, err := ts.teams.GetReviewerRotation(ctx, "qa")
+	if err != nil {
+		t.Fatalf("get rotation: %v", err)
+	}
+	if rotation.LastUserID != "q2" {
+		t.Fatalf("rotation stopped at %q, want q2", rotation.LastUserID)
+	}
+}
+
+func TestDeleteTeamKeepsMembersReferencedByPRs(t *testing.T) {
+	ts := newTestServices(t)
+	ctx := context.Background()
+	ts.createTeam(t, "backend", "u1", "u2")
+
+	if _, err := ts.prs.CreatePR(ctx, "pr-1", "pr-1", "u1", nil); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := ts.prs.MergePR(ctx, "pr-1", 0); err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	if _, err := ts.teams.DeleteTeam(ctx, "backend", ""); err != nil {
+		t.Fatalf("delete team: %v", err)
+	}
+
+	details, err := ts.prs.GetPR(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("get PR: %v", err)
+	}
+	if details.Author == nil || details.Author.TeamName != "" {
+		t.Fatalf("author = %+v, want a user without team", details.Author)
+	}
+	if len(details.Reviewers) != 1 || details.Reviewers[0].Username != "u2" {
+		t.Fatalf("reviewers = %+v, want stored u2", details.Reviewers)
+	}
+}
@@ -0,0 +1,8 @@
+package service
+
+import (
+	"go.opentelemetry.io/otel"
+)
+
+// tracer открывает спан на каждый публичный метод сервисов; доменные ошибки отмечаются в спане в domainError
+var tracer = otel.Tracer("github.com/guverz/pr-reviewer-service/internal/service")
@@ -2,52 +2,226 @@ package service
 
 import (
 	"context"
+	"fmt"
+
+	"github.com/rs/zerolog"
 
 	"github.com/guverz/pr-reviewer-service/internal/domain"
 	"github.com/guverz/pr-reviewer-service/internal/repository"
 )
 
 type UserService struct {
-	userRepo repository.UserRepository
-	teamRepo repository.TeamRepository
+	userRepo   repository.UserRepository
+	teamRepo   repository.TeamRepository
+	txMgr      repository.TransactionManager
+	authorizer *Authorizer
 }
 
-func NewUserService(userRepo repository.UserRepository, teamRepo repository.TeamRepository) *UserService {
+func NewUserService(
+	userRepo repository.UserRepository,
+	teamRepo repository.TeamRepository,
+	txMgr repository.TransactionManager,
+	authorizer *Authorizer,
+) *UserService {
 	return &UserService{
-		userRepo: userRepo,
-		teamRepo: teamRepo,
+		userRepo:   userRepo,
+		teamRepo:   teamRepo,
+		txMgr:      txMgr,
+		authorizer: authorizer,
 	}
 }
 
+// UserUpdate — изменения пользователя; nil-поля не меняются, пустой Skills удаляет все теги
+type UserUpdate struct {
+	IsActive       *bool
+	MaxOpenReviews *int
+	Skills         *[]string
+}
+
+// UpdateUser применяет все изменения пользователя в одной транзакции: либо проверки проходят
+// и применяются все поля, либо не меняется ничего. Теги может менять сам пользователь,
+// остальное — лид его команды или администратор.
+func (s *UserService) UpdateUser(ctx context.Context, userID string, update UserUpdate) (*domain.User, error) {
+	ctx, span := tracer.Start(ctx, "UserService.UpdateUser")
+	defer span.End()
+
+	if update.MaxOpenReviews != nil && *update.MaxOpenReviews < 0 {
+		return nil, domainError(ctx, domain.ErrorCodeValidation, "max_open_reviews must not be negative")
+	}
+	var skills []string
+	if update.Skills != nil {
+		skills = domain.NormalizeSkills(*update.Skills)
+	}
+
+	var user *domain.User
+	err := s.txMgr.WithinTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		user, err = s.userRepo.GetByID(txCtx, userID)
+		if err != nil {
+			return lookupError(ctx, err, "user not found", "user_id", userID)
+		}
+
+		// Все проверки прав до первой записи, потому что отката нет
+		selfSkillsOnly := update.IsActive == nil && update.MaxOpenReviews == nil && s.authorizer.IsCaller(ctx, userID)
+		if !selfSkillsOnly {
+			if err := s.authorizer.Authorize(txCtx, domain.PermissionManageMembers, user.TeamName); err != nil {
+				return err
+			}
+		}
+
+		if update.IsActive != nil {
+			if user, err = s.userRepo.SetActive(txCtx, userID, *update.IsActive); err != nil {
+				return lookupError(ctx, err, "user not found", "user_id", userID)
+			}
+			if err := s.teamRepo.UpdateMember(txCtx, user.TeamName, userID, *update.IsActive); err != nil {
+				return fmt.Errorf("update team member %s/%s: %w", user.TeamName, userID, err)
+			}
+		}
+		if update.MaxOpenReviews != nil {
+			if user, err = s.userRepo.SetMaxOpenReviews(txCtx, userID, *update.MaxOpenReviews); err != nil {
+				return lookupError(ctx, err, "user not found", "user_id", userID)
+			}
+		}
+		if update.Skills != nil {
+			if user, err = s.userRepo.SetSkills(txCtx, userID, skills); err != nil {
+				return lookupError(ctx, err, "user not found", "user_id", userID)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	event := zerolog.Ctx(ctx).Info().
+		Str("user_id", userID).
+		Str("team_name", user.TeamName)
+	if update.IsActive != nil {
+		event = event.Bool("is_active", user.IsActive)
+	}
+	if update.MaxOpenReviews != nil {
+		event = event.Int("max_open_reviews", user.MaxOpenReviews)
+	}
+	if update.Skills != nil {
+		event = event.Strs("skills", user.Skills)
+	}
+	event.Msg("user updated")
+
+	return user, nil
+}
+
 // SetActive устанавливает флаг активности пользователя и синхронизирует данные в команде
 func (s *UserService) SetActive(ctx context.Context, userID string, isActive bool) (*domain.User, error) {
+	ctx, span := tracer.Start(ctx, "UserService.SetActive")
+	defer span.End()
+
 	// Получаем пользователя для получения teamName
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
-		return nil, domain.NewDomainError(domain.ErrorCodeNotFound, "user not found")
+		return nil, lookupError(ctx, err, "user not found", "user_id", userID)
+	}
+
+	// Менять активность могут администраторы и лиды команды пользователя
+	if err := s.authorizer.Authorize(ctx, domain.PermissionManageMembers, user.TeamName); err != nil {
+		return nil, err
 	}
 
 	// Обновляем статус активности в UserRepository
 	updatedUser, err := s.userRepo.SetActive(ctx, userID, isActive)
 	if err != nil {
-		return nil, domain.NewDomainError(domain.ErrorCodeNotFound, "user not found")
+		return nil, lookupError(ctx, err, "user not found", "user_id", userID)
 	}
 
 	// Синхронизируем данные в TeamRepository
 	if err := s.teamRepo.UpdateMember(ctx, user.TeamName, userID, isActive); err != nil {
 		// Возвращаем ошибку для отладки - если команда или участник не найдены,
 		// это означает проблему синхронизации данных
-		return nil, domain.NewDomainError(domain.ErrorCodeNotFound, "failed to update team member: "+err.Error())
+		return nil, fmt.Errorf("update team member %s/%s: %w", user.TeamName, userID, err)
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("user_id", userID).
+		Str("team_name", user.TeamName).
+		Bool("is_active", isActive).
+		Msg("user activity changed")
+
+	return updatedUser, nil
+}
+
+// SetMaxOpenReviews задаёт лимит одновременных открытых ревью пользователя; 0 снимает ограничение.
+// Лимит не снимает уже назначенные ревью, а только исключает пользователя из выбора новых.
+func (s *UserService) SetMaxOpenReviews(ctx context.Context, userID string, limit int) (*domain.User, error) {
+	ctx, span := tracer.Start(ctx, "UserService.SetMaxOpenReviews")
+	defer span.End()
+
+	if limit < 0 {
+		return nil, domainError(ctx, domain.ErrorCodeValidation, "max_open_reviews must not be negative")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, lookupError(ctx, err, "user not found", "user_id", userID)
+	}
+
+	// Менять лимит могут администраторы и лиды команды пользователя
+	if err := s.authorizer.Authorize(ctx, domain.PermissionManageMembers, user.TeamName); err != nil {
+		return nil, err
+	}
+
+	updatedUser, err := s.userRepo.SetMaxOpenReviews(ctx, userID, limit)
+	if err != nil {
+		return nil, lookupError(ctx, err, "user not found", "user_id", userID)
 	}
 
+	zerolog.Ctx(ctx).Info().
+		Str("user_id", userID).
+		Str("team_name", user.TeamName).
+		Int("max_open_reviews", limit).
+		Msg("user review limit changed")
+
+	return updatedUser, nil
+}
+
+// SetSkills заменяет теги экспертизы пользователя; пустой список удаляет все теги.
+// Теги меняет сам пользователь, лид его команды или администратор.
+func (s *UserService) SetSkills(ctx context.Context, userID string, skills []string) (*domain.User, error) {
+	ctx, span := tracer.Start(ctx, "UserService.SetSkills")
+	defer span.End()
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, lookupError(ctx, err, "user not found", "user_id", userID)
+	}
+
+	if !s.authorizer.IsCaller(ctx, userID) {
+		if err := s.authorizer.Authorize(ctx, domain.PermissionManageMembers, user.TeamName); err != nil {
+			return nil, err
+		}
+	}
+
+	skills = domain.NormalizeSkills(skills)
+	updatedUser, err := s.userRepo.SetSkills(ctx, userID, skills)
+	if err != nil {
+		return nil, lookupError(ctx, err, "user not found", "user_id", userID)
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("user_id", userID).
+		Str("team_name", user.TeamName).
+		Strs("skills", skills).
+		Msg("user skills changed")
+
 	return updatedUser, nil
 }
 
 // GetUser получает пользователя по ID
 func (s *UserService) GetUser(ctx context.Context, userID string) (*domain.User, error) {
+	ctx, span := tracer.Start(ctx, "UserService.GetUser")
+	defer span.End()
+
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
-		return nil, domain.NewDomainError(domain.ErrorCodeNotFound, "user not found")
+		return nil, lookupError(ctx, err, "user not found", "user_id", userID)
 	}
 	return user, nil
 }
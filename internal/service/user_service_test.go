@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/guverz/pr-reviewer-service/internal/auth"
+	"github.com/guverz/pr-reviewer-service/internal/domain"
+)
+
+func TestUpdateUser(t *testing.T) {
+	ts := newTestServices(t)
+	ts.createTeam(t, "backend", "u1", "u2")
+	self := auth.WithIdentity(context.Background(), auth.Identity{UserID: "u2", Scope: auth.ScopeUser})
+
+	inactive, limit, skills := false, 3, []string{"SQL", "go"}
+	user, err := ts.users.UpdateUser(context.Background(), "u2", UserUpdate{IsActive: &inactive, MaxOpenReviews: &limit, Skills: &skills})
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if user.IsActive || user.MaxOpenReviews != 3 || !slices.Equal(user.Skills, []string{"go", "sql"}) {
+		t.Fatalf("unexpected user %+v", user)
+	}
+	team, err := ts.teams.GetTeam(context.Background(), "backend")
+	if err != nil {
+		t.Fatalf("get team: %v", err)
+	}
+	if team.Members[1].IsActive {
+		t.Fatal("team roster was not updated")
+	}
+
+	// Свои теги пользователь меняет сам, а вместе с активностью запрос отклоняется целиком
+	active, rust := true, []string{"rust"}
+	if _, err := ts.users.UpdateUser(self, "u2", UserUpdate{IsActive: &active, Skills: &rust}); !errors.Is(err, domain.ErrorCodeForbidden) {
+		t.Fatalf("expected %s, got %v", domain.ErrorCodeForbidden, err)
+	}
+	if user, err = ts.users.GetUser(context.Background(), "u2"); err != nil || user.IsActive || !slices.Equal(user.Skills, []string{"go", "sql"}) {
+		t.Fatalf("refused update changed the user: %+v, %v", user, err)
+	}
+	if user, err = ts.users.UpdateUser(self, "u2", UserUpdate{Skills: &rust}); err != nil || !slices.Equal(user.Skills, rust) {
+		t.Fatalf("self skills update: %+v, %v", user, err)
+	}
+
+	negative := -1
+	if _, err := ts.users.UpdateUser(context.Background(), "u2", UserUpdate{MaxOpenReviews: &negative}); !errors.Is(err, domain.ErrorCodeValidation) {
+		t.Fatalf("expected %s, got %v", domain.ErrorCodeValidation, err)
+	}
+	if _, err := ts.users.UpdateUser(context.Background(), "missing", UserUpdate{IsActive: &active}); !errors.Is(err, domain.ErrorCodeNotFound) {
+		t.Fatalf("expected %s, got %v", domain.ErrorCodeNotFound, err)
+	}
+}
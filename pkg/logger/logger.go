@@ -1,14 +1,34 @@
 package logger
 
 import (
+	"fmt"
+	"io"
 	"os"
+	"time"
 
 	"github.com/rs/zerolog"
 )
 
-func New() zerolog.Logger {
-	return zerolog.New(os.Stdout).With().Timestamp().Logger()
-}
+const (
+	FormatJSON    = "json"
+	FormatConsole = "console"
+)
 
+// New создаёт логгер с уровнем level (debug, info, warn, error...) и форматом json или console
+func New(level, format string) (zerolog.Logger, error) {
+	lvl, err := zerolog.ParseLevel(level)
+	if err != nil {
+		return zerolog.Nop(), fmt.Errorf("parse log level: %w", err)
+	}
 
+	var out io.Writer = os.Stdout
+	switch format {
+	case FormatJSON, "":
+	case FormatConsole:
+		out = zerolog.ConsoleWriter{Out: os.Stdout, TimeFormat: time.RFC3339}
+	default:
+		return zerolog.Nop(), fmt.Errorf("unknown log format %q", format)
+	}
 
+	return zerolog.New(out).Level(lvl).With().Timestamp().Logger(), nil
+}
@@ -0,0 +1,67 @@
+package tracing
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+func TestFileExporterContinuesIncomingTrace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+
+	shutdown, err := Setup(context.Background(), Config{
+		Exporter:    ExporterFile,
+		File:        path,
+		SampleRatio: 1,
+		ServiceName: "test",
+	})
+	if err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+
+	carrier := propagation.MapCarrier{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), carrier)
+	_, span := otel.Tracer("test").Start(ctx, "operation")
+	span.End()
+
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open trace file: %v", err)
+	}
+	defer file.Close()
+
+	type exportedSpan struct {
+		Name        string
+		SpanContext struct{ TraceID string }
+		Parent      struct{ SpanID string }
+	}
+	var spans []exportedSpan
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var s exportedSpan
+		if err := json.Unmarshal(scanner.Bytes(), &s); err != nil {
+			t.Fatalf("decode span: %v", err)
+		}
+		spans = append(spans, s)
+	}
+
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	if spans[0].Name != "operation" {
+		t.Fatalf("got span %q, want operation", spans[0].Name)
+	}
+	if spans[0].SpanContext.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || spans[0].Parent.SpanID != "00f067aa0ba902b7" {
+		t.Fatalf("span does not continue incoming trace: %+v", spans[0])
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sync"
 	"testing"
 	"time"
 )
@@ -389,3 +390,615 @@ func TestServerHealth(t *testing.T) {
 		t.Fatalf("Health check не прошёл: статус %d", resp.StatusCode)
 	}
 }
+
+// Ресурсные маршруты /api/v1 работают поверх тех же обработчиков, что и старые маршруты
+func TestAPIV1(t *testing.T) {
+	teamName := uniqueID("team-v1")
+	author := uniqueID("v1-author")
+	reviewer := uniqueID("v1-reviewer")
+	team := map[string]interface{}{
+		"team_name": teamName,
+		"members": []map[string]interface{}{
+			{"user_id": author, "username": "Author", "is_active": true},
+			{"user_id": reviewer, "username": "Reviewer", "is_active": true},
+		},
+	}
+
+	if status, body := doJSON(t, http.MethodPost, "/api/v1/teams", team); status != http.StatusCreated {
+		t.Fatalf("создание команды: статус %d, ответ %v", status, body)
+	}
+	if status, _ := doJSON(t, http.MethodPost, "/api/v1/teams", team); status != http.StatusConflict {
+		t.Errorf("повторное создание команды: ожидался 409, получен %d", status)
+	}
+
+	prID := uniqueID("pr-v1")
+	pr := map[string]interface{}{"pull_request_id": prID, "pull_request_name": "v1", "author_id": author}
+	if status, body := doJSON(t, http.MethodPost, "/api/v1/pull-requests", pr); status != http.StatusCreated {
+		t.Fatalf("создание PR: статус %d, ответ %v", status, body)
+	}
+	if status, _ := doJSON(t, http.MethodPost, "/api/v1/pull-requests", pr); status != http.StatusConflict {
+		t.Errorf("повторное создание PR: ожидался 409, получен %d", status)
+	}
+
+	status, body := doJSON(t, http.MethodGet, "/api/v1/pull-requests/"+prID, nil)
+	if status != http.StatusOK || body["pr"].(map[string]interface{})["pull_request_id"] != prID {
+		t.Fatalf("получение PR: статус %d, ответ %v", status, body)
+	}
+
+	status, body = doJSON(t, http.MethodPost, "/api/v1/pull-requests/"+prID+"/merge", nil)
+	if status != http.StatusOK || body["pr"].(map[string]interface{})["status"] != "MERGED" {
+		t.Fatalf("merge PR: статус %d, ответ %v", status, body)
+	}
+
+	status, body = doJSON(t, http.MethodPatch, "/api/v1/users/"+reviewer, map[string]interface{}{"is_active": false})
+	if status != http.StatusOK || body["user"].(map[string]interface{})["is_active"] != false {
+		t.Fatalf("деактивация пользователя: статус %d, ответ %v", status, body)
+	}
+
+	if status, _ := doJSON(t, http.MethodDelete, "/api/v1/pull-requests/"+prID, nil); status != http.StatusMethodNotAllowed {
+		t.Errorf("DELETE PR: ожидался 405, получен %d", status)
+	}
+}
+
+func TestConcurrentCreatePR(t *testing.T) {
+	teamName := uniqueID("team-race")
+	author := uniqueID("race-author")
+	createTeam(t, map[string]interface{}{
+		"team_name": teamName,
+		"members": []map[string]interface{}{
+			{"user_id": author, "username": "Author", "is_active": true},
+			{"user_id": uniqueID("race-r1"), "username": "Reviewer 1", "is_active": true},
+			{"user_id": uniqueID("race-r2"), "username": "Reviewer 2", "is_active": true},
+		},
+	})
+
+	const attempts = 50
+	payload := mustJSON(map[string]string{
+		"pull_request_id":   uniqueID("pr-race"),
+		"pull_request_name": "Race",
+		"author_id":         author,
+	})
+
+	type outcome struct {
+		status int
+		code   string
+		err    error
+	}
+	outcomes := make([]outcome, attempts)
+
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+
+			resp, err := http.Post(baseURL+"/pullRequest/create", "application/json", bytes.NewReader(payload))
+			if err != nil {
+				outcomes[i] = outcome{err: err}
+				return
+			}
+			defer resp.Body.Close()
+
+			var body struct {
+				Error struct {
+					Code string `json:"code"`
+				} `json:"error"`
+			}
+			json.NewDecoder(resp.Body).Decode(&body)
+			outcomes[i] = outcome{status: resp.StatusCode, code: body.Error.Code}
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	created := 0
+	for i, o := range outcomes {
+		switch {
+		case o.err != nil:
+			t.Errorf("запрос %d: %v", i, o.err)
+		case o.status == http.StatusCreated:
+			created++
+		case o.status == http.StatusBadRequest && o.code == "PR_EXISTS":
+		default:
+			t.Errorf("запрос %d: неожиданный ответ %d %s", i, o.status, o.code)
+		}
+	}
+	if created != 1 {
+		t.Errorf("ожидалось ровно одно успешное создание, получено %d", created)
+	}
+}
+
+func TestOptimisticConcurrency(t *testing.T) {
+	teamName := uniqueID("team-occ")
+	author := uniqueID("occ-author")
+	members := []map[string]interface{}{{"user_id": author, "username": "Author", "is_active": true}}
+	for i := 0; i < 4; i++ {
+		members = append(members, map[string]interface{}{
+			"user_id": uniqueID(fmt.Sprintf("occ-r%d", i)), "username": "Reviewer", "is_active": true,
+		})
+	}
+	createTeam(t, map[string]interface{}{"team_name": teamName, "members": members})
+
+	pr := createPR(t, uniqueID("pr-occ"), "OCC", author)
+	prID := pr["pull_request_id"].(string)
+	reviewers := pr["assigned_reviewers"].([]interface{})
+	if len(reviewers) != 2 {
+		t.Fatalf("ожидалось 2 ревьюера, получено %v", reviewers)
+	}
+
+	reassign := func(oldUserID, ifMatch string) (int, string, string) {
+		body := mustJSON(map[string]string{"pull_request_id": prID, "old_user_id": oldUserID})
+		req, _ := http.NewRequest(http.MethodPost, baseURL+"/pullRequest/reassign", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return 0, "", err.Error()
+		}
+		defer resp.Body.Close()
+
+		var result struct {
+			Error struct {
+				Code string `json:"code"`
+			} `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&result)
+		return resp.StatusCode, resp.Header.Get("ETag"), result.Error.Code
+	}
+
+	// Параллельные переназначения разных ревьюеров не затирают друг друга
+	var wg sync.WaitGroup
+	statuses := make([]int, len(reviewers))
+	for i, reviewer := range reviewers {
+		wg.Add(1)
+		go func(i int, reviewer string) {
+			defer wg.Done()
+			statuses[i], _, _ = reassign(reviewer, "")
+		}(i, reviewer.(string))
+	}
+	wg.Wait()
+	for i, status := range statuses {
+		if status != http.StatusOK {
+			t.Fatalf("переназначение %d: статус %d", i, status)
+		}
+	}
+
+	status, body := doJSON(t, http.MethodGet, "/api/v1/pull-requests/"+prID, nil)
+	if status != http.StatusOK {
+		t.Fatalf("получение PR: статус %d", status)
+	}
+	current := body["pr"].(map[string]interface{})
+	if got := len(current["reassignments"].([]interface{})); got != 2 {
+		t.Errorf("ожидалось 2 записи о переназначении, получено %d", got)
+	}
+	if current["version"] != float64(3) {
+		t.Errorf("ожидалась версия 3, получена %v", current["version"])
+	}
+
+	// Запись по устаревшему ETag отклоняется с 412, по актуальному — проходит
+	reviewer := current["assigned_reviewers"].([]interface{})[0].(string)
+	if status, _, code := reassign(reviewer, `"1"`); status != http.StatusPreconditionFailed || code != "PRECONDITION_FAILED" {
+		t.Errorf("устаревший If-Match: ожидался 412 PRECONDITION_FAILED, получен %d %s", status, code)
+	}
+	status, etag, code := reassign(reviewer, `"3"`)
+	if status != http.StatusOK || etag != `"4"` {
+		t.Errorf("актуальный If-Match: статус %d, ETag %s, код %s", status, etag, code)
+	}
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	teamName := uniqueID("team-idem")
+	author := uniqueID("idem-author")
+	members := []map[string]interface{}{{"user_id": author, "username": "Author", "is_active": true}}
+	for i := 0; i < 4; i++ {
+		members = append(members, map[string]interface{}{
+			"user_id": uniqueID(fmt.Sprintf("idem-r%d", i)), "username": "Reviewer", "is_active": true,
+		})
+	}
+	createTeam(t, map[string]interface{}{"team_name": teamName, "members": members})
+
+	pr := createPR(t, uniqueID("pr-idem"), "Idempotency", author)
+	prID := pr["pull_request_id"].(string)
+	reviewers := pr["assigned_reviewers"].([]interface{})
+	key := uniqueID("idem-key")
+
+	reassign := func(oldUserID string) (int, string, map[string]interface{}) {
+		body := mustJSON(map[string]string{"pull_request_id": prID, "old_user_id": oldUserID})
+		req, _ := http.NewRequest(http.MethodPost, baseURL+"/pullRequest/reassign", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Idempotency-Key", key)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Ошибка запроса: %v", err)
+		}
+		defer resp.Body.Close()
+
+		var result map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&result)
+		return resp.StatusCode, resp.Header.Get("Idempotent-Replayed"), result
+	}
+
+	// Повтор с тем же ключом возвращает сохранённый ответ и не переназначает ревьюера второй раз
+	status, replayed, first := reassign(reviewers[0].(string))
+	if status != http.StatusOK || replayed != "" {
+		t.Fatalf("первый запрос: статус %d, Idempotent-Replayed %q, ответ %v", status, replayed, first)
+	}
+	status, replayed, second := reassign(reviewers[0].(string))
+	if status != http.StatusOK || replayed != "true" {
+		t.Fatalf("повтор: статус %d, Idempotent-Replayed %q, ответ %v", status, replayed, second)
+	}
+	if first["replaced_by"] != second["replaced_by"] {
+		t.Errorf("повтор вернул другого ревьюера: %v и %v", first["replaced_by"], second["replaced_by"])
+	}
+
+	status, body := doJSON(t, http.MethodGet, "/api/v1/pull-requests/"+prID, nil)
+	if status != http.StatusOK {
+		t.Fatalf("получение PR: статус %d", status)
+	}
+	if got := len(body["pr"].(map[string]interface{})["reassignments"].([]interface{})); got != 1 {
+		t.Errorf("ожидалось 1 переназначение, получено %d", got)
+	}
+
+	// Тот же ключ с другим телом отклоняется
+	status, _, result := reassign(reviewers[1].(string))
+	if status != http.StatusUnprocessableEntity {
+		t.Fatalf("другой запрос с тем же ключом: ожидался 422, получен %d, ответ %v", status, result)
+	}
+	if code := result["error"].(map[string]interface{})["code"]; code != "IDEMPOTENCY_KEY_REUSED" {
+		t.Errorf("ожидался IDEMPOTENCY_KEY_REUSED, получен %v", code)
+	}
+}
+
+func TestUnavailability(t *testing.T) {
+	teamName := uniqueID("team-away")
+	author := uniqueID("away-author")
+	available := uniqueID("away-available")
+	onVacation := []string{uniqueID("away-r1"), uniqueID("away-r2")}
+	members := []map[string]interface{}{
+		{"user_id": author, "username": "Author", "is_active": true},
+		{"user_id": available, "username": "Available", "is_active": true},
+	}
+	for _, userID := range onVacation {
+		members = append(members, map[string]interface{}{"user_id": userID, "username": "Away", "is_active": true})
+	}
+	createTeam(t, map[string]interface{}{"team_name": teamName, "members": members})
+
+	now := time.Now().UTC()
+	windowIDs := make([]string, 0, len(onVacation))
+	for _, userID := range onVacation {
+		status, body := doJSON(t, http.MethodPost, "/api/v1/users/"+userID+"/unavailability", map[string]interface{}{
+			"reason":    "vacation",
+			"starts_at": now.Add(-time.Hour).Format(time.RFC3339),
+			"ends_at":   now.Add(24 * time.Hour).Format(time.RFC3339),
+		})
+		if status != http.StatusCreated {
+			t.Fatalf("добавление отпуска: статус %d, ответ %v", status, body)
+		}
+		windowIDs = append(windowIDs, body["unavailability"].(map[string]interface{})["id"].(string))
+	}
+
+	// Пользователи в отпуске не назначаются ревьюерами
+	for i := 0; i < 5; i++ {
+		pr := createPR(t, uniqueID("pr-away"), "Away", author)
+		reviewers := pr["assigned_reviewers"].([]interface{})
+		if len(reviewers) != 1 || reviewers[0] != available {
+			t.Fatalf("ожидался единственный ревьюер %s, получено %v", available, reviewers)
+		}
+	}
+
+	status, body := doJSON(t, http.MethodGet, "/api/v1/users/"+onVacation[0]+"/unavailability", nil)
+	if status != http.StatusOK || len(body["unavailability"].([]interface{})) != 1 {
+		t.Fatalf("список интервалов: статус %d, ответ %v", status, body)
+	}
+
+	status, body = doJSON(t, http.MethodPost, "/api/v1/users/"+onVacation[0]+"/unavailability", map[string]interface{}{
+		"reason":    "vacation",
+		"starts_at": now.Format(time.RFC3339),
+		"ends_at":   now.Add(-time.Hour).Format(time.RFC3339),
+	})
+	if status != http.StatusBadRequest {
+		t.Errorf("интервал с концом раньше начала: ожидался 400, получен %d, ответ %v", status, body)
+	}
+
+	// После удаления интервалов пользователи снова доступны
+	for i, userID := range onVacation {
+		if status, _ := doJSON(t, http.MethodDelete, "/api/v1/users/"+userID+"/unavailability/"+windowIDs[i], nil); status != http.StatusNoContent {
+			t.Fatalf("удаление интервала: ожидался 204, получен %d", status)
+		}
+	}
+	if status, _ := doJSON(t, http.MethodDelete, "/api/v1/users/"+onVacation[0]+"/unavailability/"+windowIDs[0], nil); status != http.StatusNotFound {
+		t.Errorf("повторное удаление интервала: ожидался 404, получен %d", status)
+	}
+	pr := createPR(t, uniqueID("pr-back"), "Back", author)
+	if got := len(pr["assigned_reviewers"].([]interface{})); got != 2 {
+		t.Errorf("после отпуска ожидалось 2 ревьюера, получено %d", got)
+	}
+}
+
+func TestReviewCapacity(t *testing.T) {
+	teamName := uniqueID("team-cap")
+	author := uniqueID("cap-author")
+	reviewers := []string{uniqueID("cap-r1"), uniqueID("cap-r2")}
+	members := []map[string]interface{}{{"user_id": author, "username": "Author", "is_active": true}}
+	for _, userID := range reviewers {
+		members = append(members, map[string]interface{}{"user_id": userID, "username": "Reviewer", "is_active": true})
+	}
+	createTeam(t, map[string]interface{}{"team_name": teamName, "members": members})
+
+	for _, userID := range reviewers {
+		status, body := doJSON(t, http.MethodPatch, "/api/v1/users/"+userID, map[string]interface{}{"max_open_reviews": 1})
+		if status != http.StatusOK || body["user"].(map[string]interface{})["max_open_reviews"] != float64(1) {
+			t.Fatalf("установка лимита: статус %d, ответ %v", status, body)
+		}
+	}
+
+	first := createPR(t, uniqueID("pr-cap"), "Capacity", author)
+	if got := len(first["assigned_reviewers"].([]interface{})); got != 2 {
+		t.Fatalf("ожидалось 2 ревьюера, получено %d", got)
+	}
+
+	// У всех исчерпан лимит: PR создаётся без ревьюеров с указанием причины
+	second := createPR(t, uniqueID("pr-cap"), "Capacity", author)
+	if got := len(second["assigned_reviewers"].([]interface{})); got != 0 {
+		t.Errorf("ожидалось 0 ревьюеров, получено %d", got)
+	}
+	if second["need_more_reviewers"] != true || second["reviewer_shortage"] != "at_capacity" {
+		t.Errorf("ожидались need_more_reviewers и reviewer_shortage=at_capacity, получено %v", second)
+	}
+
+	status, body := doJSON(t, http.MethodPost, "/pullRequest/reassign", map[string]interface{}{
+		"pull_request_id": first["pull_request_id"], "old_user_id": reviewers[0],
+	})
+	if status != http.StatusConflict {
+		t.Fatalf("переназначение без свободных ревьюеров: ожидался 409, получен %d, ответ %v", status, body)
+	}
+	errBody := body["error"].(map[string]interface{})
+	if errBody["code"] != "NO_CANDIDATE" || errBody["details"].(map[string]interface{})["reason"] != "at_capacity" {
+		t.Errorf("ожидался NO_CANDIDATE с причиной at_capacity, получено %v", errBody)
+	}
+
+	// Снятие лимита возвращает пользователя в выбор
+	status, body = doJSON(t, http.MethodPost, "/users/setMaxOpenReviews", map[string]interface{}{
+		"user_id": reviewers[1], "max_open_reviews": 0,
+	})
+	if status != http.StatusOK {
+		t.Fatalf("снятие лимита: статус %d, ответ %v", status, body)
+	}
+	third := createPR(t, uniqueID("pr-cap"), "Capacity", author)
+	assigned := third["assigned_reviewers"].([]interface{})
+	if len(assigned) != 1 || assigned[0] != reviewers[1] {
+		t.Errorf("ожидался единственный ревьюер %s, получено %v", reviewers[1], assigned)
+	}
+
+	if status, _ := doJSON(t, http.MethodPatch, "/api/v1/users/"+reviewers[0], map[string]interface{}{"max_open_reviews": -1}); status != http.StatusBadRequest {
+		t.Errorf("отрицательный лимит: ожидался 400, получен %d", status)
+	}
+}
+
+func TestReviewerSkills(t *testing.T) {
+	teamName := uniqueID("team-skills")
+	author := uniqueID("sk-author")
+	expert := uniqueID("sk-expert")
+	others := []string{uniqueID("sk-r1"), uniqueID("sk-r2")}
+	members := []map[string]interface{}{
+		{"user_id": author, "username": "Author", "is_active": true},
+		{"user_id": expert, "username": "Expert", "is_active": true},
+	}
+	for _, userID := range others {
+		members = append(members, map[string]interface{}{"user_id": userID, "username": "Reviewer", "is_active": true})
+	}
+	createTeam(t, map[string]interface{}{"team_name": teamName, "members": members})
+
+	// Теги приводятся к нижнему регистру, повторы убираются
+	status, body := doJSON(t, http.MethodPatch, "/api/v1/users/"+expert, map[string]interface{}{"skills": []string{"Go", "sql", "go"}})
+	if status != http.StatusOK {
+		t.Fatalf("установка тегов: статус %d, ответ %v", status, body)
+	}
+	if skills := body["user"].(map[string]interface{})["skills"]; fmt.Sprint(skills) != "[go sql]" {
+		t.Errorf("ожидались теги [go sql], получено %v", skills)
+	}
+
+	// Эксперт занимает одно из мест в каждом PR, где нужен его тег
+	for i := 0; i < 5; i++ {
+		status, body := doJSON(t, http.MethodPost, "/api/v1/pull-requests", map[string]interface{}{
+			"pull_request_id":   uniqueID("pr-skills"),
+			"pull_request_name": "Skills",
+			"author_id":         author,
+			"required_skills":   []string{"go", "frontend"},
+		})
+		if status != http.StatusCreated {
+			t.Fatalf("создание PR: статус %d, ответ %v", status, body)
+		}
+		pr := body["pr"].(map[string]interface{})
+		hasExpert := false
+		for _, reviewerID := range pr["assigned_reviewers"].([]interface{}) {
+			hasExpert = hasExpert || reviewerID == expert
+		}
+		if !hasExpert {
+			t.Fatalf("эксперт %s не назначен: %v", expert, pr["assigned_reviewers"])
+		}
+		matched := pr["matched_skills"].(map[string]interface{})
+		if len(matched) != 1 || fmt.Sprint(matched[expert]) != "[go]" {
+			t.Errorf("ожидалось matched_skills {%s: [go]}, получено %v", expert, matched)
+		}
+	}
+
+	status, _ = doJSON(t, http.MethodPost, "/users/setSkills", map[string]interface{}{"user_id": expert, "skills": []string{"bad tag"}})
+	if status != http.StatusBadRequest {
+		t.Errorf("некорректный тег: ожидался 400, получен %d", status)
+	}
+}
+
+func TestRoundRobinRotation(t *testing.T) {
+	teamName := uniqueID("team-rr")
+	base := uniqueID("rr")
+	author := base + "-author"
+	// Очередь идёт в порядке user_id
+	reviewers := []string{base + "-r1", base + "-r2", base + "-r3"}
+	members := []map[string]interface{}{{"user_id": author, "username": "Author", "is_active": true}}
+	for _, userID := range reviewers {
+		members = append(members, map[string]interface{}{"user_id": userID, "username": "Reviewer", "is_active": true})
+	}
+	createTeam(t, map[string]interface{}{"team_name": teamName, "members": members})
+
+	rotationPath := "/api/v1/teams/" + teamName + "/reviewer-rotation"
+	status, body := doJSON(t, http.MethodGet, rotationPath, nil)
+	if status != http.StatusOK || body["rotation"].(map[string]interface{})["strategy"] != "random" {
+		t.Fatalf("стратегия по умолчанию: статус %d, ответ %v", status, body)
+	}
+	status, body = doJSON(t, http.MethodPut, rotationPath, map[string]interface{}{"strategy": "round_robin"})
+	if status != http.StatusOK {
+		t.Fatalf("включение round_robin: статус %d, ответ %v", status, body)
+	}
+
+	expected := [][]string{
+		{reviewers[0], reviewers[1]},
+		{reviewers[2], reviewers[0]},
+		{reviewers[1], reviewers[2]},
+	}
+	for i, want := range expected {
+		pr := createPR(t, uniqueID("pr-rr"), "Rotation", author)
+		if got := fmt.Sprint(pr["assigned_reviewers"]); got != fmt.Sprint(want) {
+			t.Errorf("PR %d: ожидались ревьюеры %v, получено %v", i+1, want, got)
+		}
+	}
+
+	status, body = doJSON(t, http.MethodGet, rotationPath, nil)
+	if last := body["rotation"].(map[string]interface{})["last_assigned_user_id"]; status != http.StatusOK || last != reviewers[2] {
+		t.Errorf("ожидался курсор на %s, получено %v (статус %d)", reviewers[2], last, status)
+	}
+
+	// Параллельные создания не получают одну позицию очереди: нагрузка делится поровну
+	const parallel = 9
+	assigned := make([][]interface{}, parallel)
+	var wg sync.WaitGroup
+	for i := 0; i < parallel; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			payload := mustJSON(map[string]string{
+				"pull_request_id":   fmt.Sprintf("%s-%d", uniqueID("pr-rr-par"), i),
+				"pull_request_name": "Rotation",
+				"author_id":         author,
+			})
+			resp, err := http.Post(baseURL+"/pullRequest/create", "application/json", bytes.NewReader(payload))
+			if err != nil {
+				return
+			}
+			defer resp.Body.Close()
+			var result map[string]interface{}
+			if json.NewDecoder(resp.Body).Decode(&result) == nil && resp.StatusCode == http.StatusCreated {
+				assigned[i] = result["pr"].(map[string]interface{})["assigned_reviewers"].([]interface{})
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	counts := make(map[interface{}]int)
+	for i, prReviewers := range assigned {
+		if len(prReviewers) != 2 {
+			t.Fatalf("PR %d: ожидалось 2 ревьюера, получено %v", i, prReviewers)
+		}
+		for _, reviewerID := range prReviewers {
+			counts[reviewerID]++
+		}
+	}
+	for _, userID := range reviewers {
+		if counts[userID] != parallel*2/len(reviewers) {
+			t.Errorf("ревьюер %s назначен %d раз, ожидалось %d", userID, counts[userID], parallel*2/len(reviewers))
+		}
+	}
+
+	if status, _ := doJSON(t, http.MethodPut, rotationPath, map[string]interface{}{"strategy": "fastest"}); status != http.StatusBadRequest {
+		t.Errorf("неизвестная стратегия: ожидался 400, получен %d", status)
+	}
+}
+
+func TestValidation(t *testing.T) {
+	userID := uniqueID("dup")
+	team := map[string]interface{}{
+		"team_name": uniqueID("team-invalid"),
+		"members": []map[string]interface{}{
+			{"user_id": userID, "username": "First", "is_active": true},
+			{"user_id": userID, "username": "", "is_active": true},
+		},
+	}
+
+	status, body := doJSON(t, http.MethodPost, "/team/add", team)
+	if status != http.StatusBadRequest {
+		t.Fatalf("ожидался 400, получен %d, ответ %v", status, body)
+	}
+	errBody := body["error"].(map[string]interface{})
+	if errBody["code"] != "VALIDATION_ERROR" {
+		t.Fatalf("ожидался VALIDATION_ERROR, получен %v", errBody["code"])
+	}
+
+	fields := make(map[string]bool)
+	for _, v := range errBody["violations"].([]interface{}) {
+		fields[v.(map[string]interface{})["field"].(string)] = true
+	}
+	for _, field := range []string{"members[1].user_id", "members[1].username"} {
+		if !fields[field] {
+			t.Errorf("нет нарушения по полю %s: %v", field, errBody["violations"])
+		}
+	}
+
+	status, body = doJSON(t, http.MethodPost, "/pullRequest/create", map[string]interface{}{"pull_request_name": "no id"})
+	if status != http.StatusBadRequest {
+		t.Errorf("создание PR без идентификаторов: ожидался 400, получен %d, ответ %v", status, body)
+	}
+}
+
+func TestProblemDetails(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, baseURL+"/api/v1/pull-requests/"+uniqueID("missing"), nil)
+	if err != nil {
+		t.Fatalf("Ошибка создания запроса: %v", err)
+	}
+	req.Header.Set("Accept", "application/problem+json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Ошибка запроса: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("ожидался 404, получен %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/problem+json" {
+		t.Errorf("ожидался Content-Type application/problem+json, получен %q", ct)
+	}
+
+	var problem map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&problem)
+	if problem["status"] != float64(http.StatusNotFound) || problem["code"] != "NOT_FOUND" {
+		t.Errorf("неожиданное тело ответа: %v", problem)
+	}
+}
+
+func doJSON(t *testing.T, method, path string, payload interface{}) (int, map[string]interface{}) {
+	var body bytes.Buffer
+	if payload != nil {
+		body.Write(mustJSON(payload))
+	}
+
+	req, err := http.NewRequest(method, baseURL+path, &body)
+	if err != nil {
+		t.Fatalf("Ошибка создания запроса: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Ошибка запроса %s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	var result map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&result)
+	return resp.StatusCode, result
+}